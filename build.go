@@ -0,0 +1,255 @@
+package govalidate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BuildError a misconfigured rule found by Build
+type BuildError struct {
+	Column string
+	Rule   string
+	Err    error
+}
+
+func (e *BuildError) Error() string {
+	if e.Rule == "" {
+		return fmt.Sprintf("govalidate: column %s: %v", e.Column, e.Err)
+	}
+	return fmt.Sprintf("govalidate: column %s: rule %s: %v", e.Column, e.Rule, e.Err)
+}
+
+// Unwrap get the cause
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
+
+// BuildErrors every misconfigured rule found by Build
+type BuildErrors []*BuildError
+
+func (es BuildErrors) Error() string {
+	messages := make([]string, 0, len(es))
+	for _, e := range es {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap get the errors for errors.Is and errors.As
+func (es BuildErrors) Unwrap() []error {
+	errs := make([]error, 0, len(es))
+	for _, e := range es {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Build check the rules and compile the schema, the error lists every misconfigured rule:
+// invalid patterns or arguments, inverted ranges, contradictory bounds of a column, conflicting aliases
+// and unregistered rule names
+func (v *Validate) Build() (*Schema, error) {
+
+	var errs BuildErrors
+
+	for _, c := range v.columns {
+
+		for _, alias := range c.aliases {
+			errs = append(errs, &BuildError{Column: c.name, Err: fmt.Errorf("declared with conflicting aliases %q and %q", c.alias, alias)})
+		}
+
+		length, number := &bounds{}, &bounds{}
+
+		for _, it := range c.rule.item {
+
+			err := it.err
+			if err == nil && it.verifyFunc == nil && it.contextFunc == nil && it.filter == nil && !it.nullable && !v.registered(it.name) {
+				err = fmt.Errorf("is not registered")
+			}
+			if check, ok := argChecks[it.name]; err == nil && ok {
+				err = check(it.args)
+			}
+			if err == nil && len(it.conditions) == 0 {
+				// the conditional rules may never apply together, only the unconditional ones are compared
+				switch it.name {
+				case "length", "lengthMin", "lengthMax", "betweenLen":
+					err = length.add(it.name, it.args)
+				case "between", "min", "max":
+					err = number.add(it.name, it.args)
+				}
+			}
+
+			if err != nil {
+				errs = append(errs, &BuildError{Column: c.name, Rule: it.name, Err: err})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return v.compile(), nil
+}
+
+// misconfigured get the rules which were misconfigured when added, e.g. an invalid pattern
+func (v *Validate) misconfigured() BuildErrors {
+
+	var errs BuildErrors
+
+	for _, c := range v.columns {
+		for _, it := range c.rule.item {
+			if it.err != nil {
+				errs = append(errs, &BuildError{Column: c.name, Rule: it.name, Err: it.err})
+			}
+		}
+	}
+
+	return errs
+}
+
+// argCheck check the literal arguments of a built-in rule, the Ref arguments are resolved at validation time
+type argCheck func(args []interface{}) error
+
+var argChecks = map[string]argCheck{
+	"between":            rangeArgs(toNumber),
+	"betweenLen":         rangeArgs(toLength),
+	"max":                valueArgs(toNumber),
+	"min":                valueArgs(toNumber),
+	"greaterThan":        valueArgs(toNumber),
+	"lessThan":           valueArgs(toNumber),
+	"length":             valueArgs(toLength),
+	"lengthMax":          valueArgs(toLength),
+	"lengthMin":          valueArgs(toLength),
+	"dateBefore":         valueArgs(toTime),
+	"dateAfter":          valueArgs(toTime),
+	"in":                 minArgs(1),
+	"notIn":              minArgs(1),
+	"requiredIf":         minArgs(2),
+	"requiredUnless":     minArgs(2),
+	"requiredWith":       minArgs(1),
+	"requiredWithAll":    minArgs(1),
+	"requiredWithout":    minArgs(1),
+	"requiredWithoutAll": minArgs(1),
+}
+
+func toNumber(arg interface{}) (float64, error) {
+	n, err := ToFloat(arg)
+	if err != nil {
+		return 0, fmt.Errorf("argument %v is not a number", display(arg))
+	}
+	return n, nil
+}
+
+func toLength(arg interface{}) (float64, error) {
+	n, err := lengthArg(arg)
+	if err != nil {
+		return 0, fmt.Errorf("argument %v is not an integer", display(arg))
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("length %d is negative", n)
+	}
+	return float64(n), nil
+}
+
+func toTime(arg interface{}) (float64, error) {
+	if _, err := ToTime(arg); err != nil {
+		return 0, fmt.Errorf("argument %v is not a time", display(arg))
+	}
+	return 0, nil
+}
+
+func valueArgs(conv func(arg interface{}) (float64, error)) argCheck {
+	return func(args []interface{}) error {
+		if len(args) != 1 {
+			return fmt.Errorf("expects 1 argument, got %d", len(args))
+		}
+		if _, ok := args[0].(Ref); ok {
+			return nil
+		}
+		_, err := conv(args[0])
+		return err
+	}
+}
+
+func rangeArgs(conv func(arg interface{}) (float64, error)) argCheck {
+	return func(args []interface{}) error {
+		if len(args) != 2 {
+			return fmt.Errorf("expects 2 arguments, got %d", len(args))
+		}
+		var values []float64
+		for _, arg := range args {
+			if _, ok := arg.(Ref); ok {
+				continue
+			}
+			n, err := conv(arg)
+			if err != nil {
+				return err
+			}
+			values = append(values, n)
+		}
+		if len(values) == 2 && values[0] > values[1] {
+			return fmt.Errorf("range %v,%v is inverted", display(args[0]), display(args[1]))
+		}
+		return nil
+	}
+}
+
+func minArgs(n int) argCheck {
+	return func(args []interface{}) error {
+		if len(args) < n {
+			return fmt.Errorf("expects at least %d arguments, got %d", n, len(args))
+		}
+		return nil
+	}
+}
+
+// bounds the literal lower and upper bounds a column collected from its rules
+type bounds struct {
+	lower, upper         float64
+	lowerRule, upperRule string
+}
+
+// add the bounds of the rule, fail if no value can satisfy both the rule and the earlier ones
+func (b *bounds) add(rule string, args []interface{}) error {
+
+	var lower, upper interface{}
+	switch rule {
+	case "length":
+		lower, upper = args[0], args[0]
+	case "lengthMin", "min":
+		lower = args[0]
+	case "lengthMax", "max":
+		upper = args[0]
+	default:
+		lower, upper = args[0], args[1]
+	}
+
+	if n, ok := literal(lower); ok {
+		if b.upperRule != "" && n > b.upper {
+			return fmt.Errorf("minimum %v contradicts the maximum %v of %s", n, b.upper, b.upperRule)
+		}
+		if b.lowerRule == "" || n > b.lower {
+			b.lower, b.lowerRule = n, rule
+		}
+	}
+
+	if n, ok := literal(upper); ok {
+		if b.lowerRule != "" && n < b.lower {
+			return fmt.Errorf("maximum %v contradicts the minimum %v of %s", n, b.lower, b.lowerRule)
+		}
+		if b.upperRule == "" || n < b.upper {
+			b.upper, b.upperRule = n, rule
+		}
+	}
+
+	return nil
+}
+
+// literal get the number of a literal argument, false for Ref and nil
+func literal(arg interface{}) (float64, bool) {
+	if _, ok := arg.(Ref); ok || arg == nil {
+		return 0, false
+	}
+	n, err := ToFloat(arg)
+	return n, err == nil
+}
@@ -0,0 +1,88 @@
+package govalidate
+
+import "fmt"
+
+// Condition predicate on the data to be validated. For a wildcard column, e.g. items.*.price,
+// the slices of the wildcards hold only the validated element, so items.*.type refers to the type of that element
+type Condition func(data M) bool
+
+// When 当 cond 成立时才执行 fn 中添加的规则, fn 中不能使用 Default, DefaultFunc, DefaultEmpty 和 As
+func (r *Rule) When(cond Condition, fn func(r *Rule)) *Rule {
+
+	sub := &Rule{}
+	fn(sub)
+
+	for _, it := range sub.item {
+		it.conditions = append([]Condition{cond}, it.conditions...)
+		r.item = append(r.item, it)
+	}
+
+	if sub.defaultFunc != nil || sub.defaultEmpty || sub.as != nil {
+		// the default and the conversion apply to the whole column, they can't depend on a condition
+		r.item = append(r.item, item{name: "when", err: fmt.Errorf("Default, DefaultFunc, DefaultEmpty and As can't be conditional")})
+	}
+
+	return r
+}
+
+// Unless 除非 cond 成立, 否则执行 fn 中添加的规则
+func (r *Rule) Unless(cond Condition, fn func(r *Rule)) *Rule {
+	return r.When(func(data M) bool { return !cond(data) }, fn)
+}
+
+// IfEqual the value of column equals one of the values, a wildcard column matches when any element equals
+func IfEqual(column string, values ...interface{}) Condition {
+	return func(data M) bool {
+		paths, _ := expand(data, normalizePath(column))
+		for _, path := range paths {
+			if matchAny(data, path, values) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// IfPresent all columns are present, a wildcard column is present in any element
+func IfPresent(columns ...string) Condition {
+	return func(data M) bool {
+		for _, column := range columns {
+			if !present(data, column) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// IfMissing all columns are missing, a wildcard column is missing in every element
+func IfMissing(columns ...string) Condition {
+	return func(data M) bool {
+		for _, column := range columns {
+			if present(data, column) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func present(data M, column string) bool {
+	paths, _ := expand(data, normalizePath(column))
+	for _, path := range paths {
+		if _, ok := Lookup(data, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// active report whether the conditions of the item hold, data is narrowed to the validated element
+func (it *item) active(data map[string]interface{}) bool {
+	for _, cond := range it.conditions {
+		if !cond(data) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package govalidate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ContextFunc context aware validate func, a non-nil error reports the lookup failed, e.g. the database is unavailable,
+// which is distinct from the data being invalid
+type ContextFunc func(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error)
+
+// LookupError a context aware rule failed to decide whether the data is valid
+type LookupError struct {
+	Field string
+	Rule  string
+	Err   error
+}
+
+func (e *LookupError) Error() string {
+	return fmt.Sprintf("govalidate: %s lookup of %s failed: %v", e.Rule, e.Field, e.Err)
+}
+
+// Unwrap get the cause
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// CustomContext 自定义验证函数, 可访问 context 并报告查询错误
+func (r *Rule) CustomContext(name string, fn ContextFunc, message string, args ...interface{}) *Rule {
+
+	r.item = append(r.item, item{
+		name:        name,
+		message:     message,
+		args:        args,
+		contextFunc: fn,
+	})
+
+	return r
+}
+
+// ValidateContext validate with the context, stop at the first failed rule,
+// the error is the context error or a *LookupError, the validation stops on it
+func (s *Schema) ValidateContext(ctx context.Context, data map[string]interface{}) (*Result, error) {
+	result := s.checkContext(ctx, data, 1)
+	return result, result.err
+}
+
+// ValidateAllContext validate every column with the context and collect all failed rules
+func (s *Schema) ValidateAllContext(ctx context.Context, data map[string]interface{}) (*Result, error) {
+	result := s.checkContext(ctx, data, s.options.maxErrors)
+	return result, result.err
+}
+
+// ValidateContext validate with the context, stop at the first failed rule
+func (v *Validate) ValidateContext(ctx context.Context, data map[string]interface{}) (bool, error) {
+	return v.runContext(ctx, data, 1)
+}
+
+// ValidateAllContext validate every column with the context and collect all failed rules
+func (v *Validate) ValidateAllContext(ctx context.Context, data map[string]interface{}) (bool, error) {
+	return v.runContext(ctx, data, v.options.maxErrors)
+}
+
+// Err get the context error or the *LookupError which stopped the validation
+func (r *Result) Err() error {
+	return r.err
+}
+
+// lookupFailure get the error of a validation stopped by err, the field and rule are those of the *LookupError
+// or of the first *BuildError
+func lookupFailure(err error) *Error {
+
+	e := &Error{errorMessage: err.Error(), err: err}
+
+	var le *LookupError
+	var be *BuildError
+	if errors.As(err, &le) {
+		e.field, e.rule = le.Field, le.Rule
+	} else if errors.As(err, &be) {
+		e.field, e.rule = be.Column, be.Rule
+	}
+
+	return e
+}
+
+// verify run the rule of the item
+func (s *Schema) verify(ctx context.Context, item item, data map[string]interface{}, path string, args []interface{}) (bool, error) {
+
+	switch {
+	case item.contextFunc != nil:
+		return item.contextFunc(ctx, data, path, args...)
+	case item.verifyFunc != nil:
+		return item.verifyFunc(data, path, args...), nil
+	}
+
+	if fn := s.lookupRule(item.name); fn != nil {
+		return fn(ctx, data, path, args...)
+	}
+
+	return false, nil
+}
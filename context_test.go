@@ -0,0 +1,255 @@
+package govalidate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryLookup in-memory stand-in of an external lookup service
+type memoryLookup struct {
+	sync.Mutex
+	values    map[string]bool
+	err       error
+	delay     time.Duration
+	calls     int
+	cancelled int
+	started   chan struct{}
+}
+
+func (m *memoryLookup) exists(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error) {
+
+	m.Lock()
+	m.calls++
+	m.Unlock()
+
+	if m.started != nil {
+		m.started <- struct{}{}
+	}
+
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			m.Lock()
+			m.cancelled++
+			m.Unlock()
+			return false, ctx.Err()
+		}
+	}
+
+	if m.err != nil {
+		return false, m.err
+	}
+
+	value, ok := Lookup(data, column)
+	return !ok || m.values[ToString(value)], nil
+}
+
+func TestValidateContext(t *testing.T) {
+
+	t.Parallel()
+
+	lookup := &memoryLookup{values: map[string]bool{"books": true}}
+
+	v := New()
+	v.AddColumn("category", "").Required("").CustomContext("categoryExists", lookup.exists, "")
+	v.AddColumn("title", "").Required("")
+
+	schema := v.Schema()
+
+	result, err := schema.ValidateContext(context.Background(), M{"category": "books", "title": "Go"})
+	if err != nil || !result.Valid() {
+		t.Fatal(err, result.FirstError())
+	}
+
+	result, err = schema.ValidateContext(context.Background(), M{"category": "toys", "title": "Go"})
+	if err != nil || result.Valid() || result.FirstError().GetRule() != "categoryExists" {
+		t.Errorf("Expected categoryExists failure, got %v %v", err, result.FirstError())
+	}
+
+	down := errors.New("connection refused")
+	failing := &memoryLookup{err: down}
+	v = New()
+	v.AddColumn("category", "").CustomContext("categoryExists", failing.exists, "")
+	v.AddColumn("title", "").Required("")
+
+	result, err = v.Schema().ValidateAllContext(context.Background(), M{"category": "books"})
+	var le *LookupError
+	if !errors.As(err, &le) || le.Field != "category" || le.Rule != "categoryExists" || !errors.Is(err, down) {
+		t.Errorf("Expected lookup error, got %v", err)
+	}
+	if result.Valid() || len(result.Errors()) != 0 || result.Err() != err {
+		t.Errorf("Expected lookup error to be distinct from validation errors, got %v", result.Errors())
+	}
+
+	if valid, err := v.ValidateContext(context.Background(), M{"category": "books"}); valid || !errors.Is(err, down) {
+		t.Errorf("Expected Validate.ValidateContext lookup error, got %v %v", valid, err)
+	}
+
+	for _, validate := range []func(data map[string]interface{}) bool{v.Validate, v.ValidateAll} {
+		if validate(M{"category": "books", "title": "Go"}) {
+			t.Fatal("Expected the lookup error to fail the validation")
+		}
+		if e := v.Error(); e == nil || e.GetField() != "category" || e.GetRule() != "categoryExists" || e.GetErrorMessage() == "" {
+			t.Errorf("Expected the lookup error as Error, got %v", e)
+		}
+		if !errors.Is(v.Err(), down) || !errors.As(v.Error(), &le) || errors.Is(v.Error(), ErrRule("categoryExists")) {
+			t.Errorf("Expected the Error to unwrap to the lookup error, got %v %v", v.Err(), v.Error())
+		}
+	}
+
+	failing.err = nil
+	if !v.Validate(M{"title": "Go"}) || v.Err() != nil || v.Error() != nil {
+		t.Errorf("Expected the lookup error to be reset, got %v %v", v.Err(), v.Error())
+	}
+}
+
+func TestValidateContextCancel(t *testing.T) {
+
+	t.Parallel()
+
+	slow := &memoryLookup{values: map[string]bool{"a": true}, delay: time.Minute}
+
+	v := New()
+	v.RegisterContextRule("slowExists", slow.exists)
+	v.AddColumn("first", "").Use("slowExists", "")
+	v.AddColumn("second", "").Use("slowExists", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := v.Schema().ValidateContext(ctx, M{"first": "a", "second": "a"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if slow.calls != 1 || slow.cancelled != 1 {
+		t.Errorf("Expected the deadline to stop the first lookup and skip the second, got %d calls, %d cancelled", slow.calls, slow.cancelled)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.Schema().ValidateContext(ctx, M{"first": "a"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected canceled, got %v", err)
+	}
+}
+
+func TestValidateConcurrency(t *testing.T) {
+
+	t.Parallel()
+
+	columns := []string{"c0", "c1", "c2"}
+
+	// every lookup waits until all of them are in flight, so the validation only completes when they run in parallel
+	var arrived sync.WaitGroup
+	arrived.Add(len(columns))
+	all := make(chan struct{})
+	go func() {
+		arrived.Wait()
+		close(all)
+	}()
+
+	barrier := func(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error) {
+		arrived.Done()
+		select {
+		case <-all:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		value, _ := Lookup(data, column)
+		return ToString(value) == "a", nil
+	}
+
+	v := New()
+	for _, name := range columns {
+		v.AddColumn(name, "").CustomContext("exists", barrier, "")
+	}
+	v.AddColumn("items.*.sku", "").Required("")
+	v.SetConcurrency(4)
+
+	data := M{"c0": "x", "c1": "a", "c2": "x", "items": []interface{}{M{"sku": "s1"}, M{}, M{"sku": ""}}}
+
+	// the timeout only turns a sequential run into a failure instead of a hang
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result, err := v.Schema().ValidateAllContext(ctx, data)
+	if err != nil {
+		t.Fatalf("Expected the lookups to run in parallel, got %v", err)
+	}
+	if fields := fmt.Sprint(result.Errors().Fields()); fields != "[c0 c2 items.1.sku items.2.sku]" {
+		t.Errorf("Expected declaration order, got %s", fields)
+	}
+	if sku, _ := Lookup(result.GetData(), "items.0.sku"); result.GetData()["c1"] != "a" || sku != "s1" {
+		t.Errorf("Unexpected data %v", result.GetData())
+	}
+
+	lookup := &memoryLookup{values: map[string]bool{"a": true}}
+
+	sequential, parallel := New(), New()
+	for _, v := range []*Validate{sequential, parallel} {
+		for _, name := range columns {
+			v.AddColumn(name, "").CustomContext("exists", lookup.exists, "")
+		}
+		v.AddColumn("items.*.sku", "").Required("")
+		v.SetMaxErrors(3)
+	}
+	parallel.SetConcurrency(4)
+
+	want, _ := sequential.Schema().ValidateAllContext(context.Background(), data)
+	got, _ := parallel.Schema().ValidateAllContext(context.Background(), data)
+	if want.Errors().Error() != got.Errors().Error() {
+		t.Errorf("Expected %q, got %q", want.Errors().Error(), got.Errors().Error())
+	}
+}
+
+func TestValidateConcurrencyFailFast(t *testing.T) {
+
+	t.Parallel()
+
+	slow := &memoryLookup{values: map[string]bool{"a": true}, delay: time.Minute, started: make(chan struct{}, 2)}
+
+	// first fails once both slow lookups are in flight, which are then cancelled
+	inFlight := func(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error) {
+		for i := 0; i < 2; i++ {
+			select {
+			case <-slow.started:
+			case <-ctx.Done():
+				return false, ctx.Err()
+			}
+		}
+		return false, nil
+	}
+
+	v := New()
+	v.AddColumn("first", "").CustomContext("inFlight", inFlight, "")
+	v.AddColumn("second", "").CustomContext("slowExists", slow.exists, "")
+	v.AddColumn("third", "").CustomContext("slowExists", slow.exists, "")
+	v.SetConcurrency(3)
+
+	result, err := v.Schema().ValidateContext(context.Background(), M{"second": "a", "third": "a"})
+	if err != nil || result.FirstError().GetField() != "first" || len(result.Errors()) != 1 {
+		t.Errorf("Expected first to fail, got %v %v", err, result.Errors())
+	}
+	if slow.calls != 2 || slow.cancelled != 2 {
+		t.Errorf("Expected the in-flight lookups to be cancelled, got %d calls, %d cancelled", slow.calls, slow.cancelled)
+	}
+
+	blocked := &memoryLookup{values: map[string]bool{"a": true}, delay: time.Minute}
+	down := errors.New("connection refused")
+	failing := &memoryLookup{err: down}
+	v = New()
+	v.AddColumn("first", "").CustomContext("slowExists", blocked.exists, "")
+	v.AddColumn("second", "").CustomContext("failingExists", failing.exists, "")
+	v.SetConcurrency(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if _, err := v.Schema().ValidateAllContext(ctx, M{"first": "a", "second": "a"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the earlier column to report the deadline, got %v", err)
+	}
+}
@@ -55,7 +55,7 @@ func ToFloat(value interface{}) (res float64, err error) {
 	return
 }
 
-// ToInt convert the input string or any int type to an integer type 64, or 0 if the input is not an integer.
+// ToInt convert the input string or any int type to an integer type 64, or 0 and an error if the input is not an integer.
 func ToInt(value interface{}) (res int64, err error) {
 	val := reflect.ValueOf(value)
 
@@ -65,11 +65,10 @@ func ToInt(value interface{}) (res int64, err error) {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		res = int64(val.Uint())
 	case reflect.String:
-		v, err := strconv.Atoi(val.String())
+		res, err = strconv.ParseInt(val.String(), 10, 64)
 		if err != nil {
 			res = 0
 		}
-		res = int64(v)
 
 	default:
 		err = fmt.Errorf("conversion failed, type is %T", value)
@@ -84,7 +83,7 @@ func ToBoolean(str string) (bool, error) {
 	return strconv.ParseBool(str)
 }
 
-// ToTime convert the input interface to a time.
+// ToTime convert the input time, RFC3339 string or unix seconds to a time, or an error if the input is none of them.
 func ToTime(value interface{}) (t time.Time, err error) {
 
 	switch value.(type) {
@@ -95,9 +94,9 @@ func ToTime(value interface{}) (t time.Time, err error) {
 		t, err = time.Parse(time.RFC3339, value.(string))
 
 	default:
-		val, err := ToInt(value)
-		if err != nil {
-			t = time.Now()
+		var val int64
+		if val, err = ToInt(value); err != nil {
+			return
 		}
 		t = time.Unix(val, 0)
 	}
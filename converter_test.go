@@ -0,0 +1,61 @@
+package govalidate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestToInt(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		value    interface{}
+		expected int64
+		err      bool
+	}{
+		{18, 18, false},
+		{uint8(7), 7, false},
+		{"-42", -42, false},
+		{"9223372036854775807", 9223372036854775807, false},
+		{"abc", 0, true},
+		{"1.5", 0, true},
+		{"", 0, true},
+		{1.5, 0, true},
+		{nil, 0, true},
+	}
+
+	for _, test := range tests {
+		res, err := ToInt(test.value)
+		if res != test.expected || (err != nil) != test.err {
+			t.Errorf("Expected %#v to be %d (error %v), got %d, %v", test.value, test.expected, test.err, res, err)
+		}
+	}
+}
+
+func TestToTime(t *testing.T) {
+
+	t.Parallel()
+
+	now := time.Now()
+
+	var tests = []*struct {
+		value    interface{}
+		expected time.Time
+		err      bool
+	}{
+		{now, now, false},
+		{"2020-01-02T03:04:05Z", time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), false},
+		{int64(1577934245), time.Unix(1577934245, 0), false},
+		{"2020-01-02", time.Time{}, true},
+		{1.5, time.Time{}, true},
+		{nil, time.Time{}, true},
+	}
+
+	for _, test := range tests {
+		res, err := ToTime(test.value)
+		if (err != nil) != test.err || (!test.err && !res.Equal(test.expected)) {
+			t.Errorf("Expected %#v to be %v (error %v), got %v, %v", test.value, test.expected, test.err, res, err)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package govalidate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Querier is the query interface of *sql.DB, *sql.Tx and *sql.Conn
+type Querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// QueryOption option of the Unique and Exists queries
+type QueryOption func(q *query)
+
+type query struct {
+	where       []condition
+	placeholder func(n int) string
+}
+
+type condition struct {
+	column   string
+	operator string
+	value    interface{}
+}
+
+var rxpIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// Where add the condition column = value to the query, the value may be a Ref
+func Where(column string, value interface{}) QueryOption {
+	return func(q *query) {
+		q.where = append(q.where, condition{column, "=", value})
+	}
+}
+
+// Ignore add the condition column <> value to the query, e.g. Ignore("id", Ref("id")) to ignore the updated row
+func Ignore(column string, value interface{}) QueryOption {
+	return func(q *query) {
+		q.where = append(q.where, condition{column, "<>", value})
+	}
+}
+
+// DollarPlaceholders use $1, $2... placeholders instead of ?, e.g. for PostgreSQL
+func DollarPlaceholders() QueryOption {
+	return func(q *query) {
+		q.placeholder = func(n int) string { return "$" + strconv.Itoa(n) }
+	}
+}
+
+// Unique 值在数据表 table 的 column 列中不存在
+func (r *Rule) Unique(db Querier, table string, column string, message string, opts ...QueryOption) *Rule {
+	return r.lookup("unique", db, table, column, false, message, opts)
+}
+
+// Exists 值在数据表 table 的 column 列中存在
+func (r *Rule) Exists(db Querier, table string, column string, message string, opts ...QueryOption) *Rule {
+	return r.lookup("exists", db, table, column, true, message, opts)
+}
+
+// lookup add the count query rule, an invalid identifier is reported by Build
+func (r *Rule) lookup(name string, db Querier, table string, column string, exists bool, message string, opts []QueryOption) *Rule {
+
+	q := &query{placeholder: func(n int) string { return "?" }}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	r.CustomContext(name, countFunc(db, q, table, column, exists), message, table, column)
+	r.item[len(r.item)-1].err = q.check(table, column)
+
+	return r
+}
+
+// countFunc count the rows matching the value, valid if the count is positive for exists, zero for unique
+func countFunc(db Querier, q *query, table string, column string, exists bool) ContextFunc {
+	return func(ctx context.Context, data map[string]interface{}, path string, args ...interface{}) (bool, error) {
+
+		value, ok := Lookup(data, path)
+		if !ok {
+			return true, nil
+		}
+
+		statement, params := q.build(table, column, value, data, path)
+
+		var count int64
+		if err := db.QueryRowContext(ctx, statement, params...).Scan(&count); err != nil {
+			return false, err
+		}
+
+		return (count > 0) == exists, nil
+	}
+}
+
+// check the identifiers, they are written into the query since they can not be parameters
+func (q *query) check(table string, column string) error {
+
+	if !rxpIdentifier.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+
+	columns := []string{column}
+	for _, c := range q.where {
+		columns = append(columns, c.column)
+	}
+
+	for _, c := range columns {
+		if !rxpIdentifier.MatchString(c) {
+			return fmt.Errorf("invalid column name %q", c)
+		}
+	}
+
+	return nil
+}
+
+// build the parameterized count query, the identifiers are checked by check
+func (q *query) build(table string, column string, value interface{}, data map[string]interface{}, path string) (string, []interface{}) {
+
+	conditions := append([]condition{{column, "=", value}}, q.where...)
+
+	clauses := make([]string, 0, len(conditions))
+	params := make([]interface{}, 0, len(conditions))
+
+	for i, c := range conditions {
+		clauses = append(clauses, c.column+" "+c.operator+" "+q.placeholder(i+1))
+		params = append(params, resolveArgs(data, path, []interface{}{c.value})[0])
+	}
+
+	return "SELECT COUNT(*) FROM " + table + " WHERE " + strings.Join(clauses, " AND "), params
+}
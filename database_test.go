@@ -0,0 +1,240 @@
+package govalidate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDriver database/sql driver over in-memory tables, it only understands the count queries of Unique and Exists
+type fakeDriver struct {
+	sync.Mutex
+	tables  map[string][]M
+	queries []string
+	err     error
+}
+
+type fakeConn struct{ driver *fakeDriver }
+
+type fakeStmt struct {
+	driver *fakeDriver
+	query  string
+}
+
+type fakeRows struct{ count int64 }
+
+var fakeDrivers = struct {
+	sync.Mutex
+	m map[string]*fakeDriver
+}{m: make(map[string]*fakeDriver)}
+
+func init() {
+	sql.Register("govalidate-fake", fakeOpener{})
+}
+
+type fakeOpener struct{}
+
+func (fakeOpener) Open(name string) (driver.Conn, error) {
+	fakeDrivers.Lock()
+	defer fakeDrivers.Unlock()
+	d, ok := fakeDrivers.m[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fake database %q", name)
+	}
+	return &fakeConn{driver: d}, nil
+}
+
+// openFake open a database over the tables
+func openFake(t *testing.T, tables map[string][]M) (*sql.DB, *fakeDriver) {
+
+	d := &fakeDriver{tables: tables}
+
+	fakeDrivers.Lock()
+	fakeDrivers.m[t.Name()] = d
+	fakeDrivers.Unlock()
+
+	db, err := sql.Open("govalidate-fake", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return db, d
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake: transactions are not supported")
+}
+
+func (s *fakeStmt) Close() error { return nil }
+
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("fake: exec is not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+
+	s.driver.Lock()
+	defer s.driver.Unlock()
+
+	s.driver.queries = append(s.driver.queries, s.query)
+	if s.driver.err != nil {
+		return nil, s.driver.err
+	}
+
+	var table, where string
+	if _, err := fmt.Sscanf(s.query, "SELECT COUNT(*) FROM %s WHERE", &table); err != nil {
+		return nil, fmt.Errorf("fake: unsupported query %q", s.query)
+	}
+	where = s.query[strings.Index(s.query, " WHERE ")+len(" WHERE "):]
+	clauses := strings.Split(where, " AND ")
+	if len(clauses) != len(args) {
+		return nil, fmt.Errorf("fake: %d placeholders, %d arguments", len(clauses), len(args))
+	}
+
+	rows := &fakeRows{}
+	for _, row := range s.driver.tables[table] {
+		match := true
+		for i, clause := range clauses {
+			parts := strings.Fields(clause)
+			equal := fmt.Sprint(row[parts[0]]) == fmt.Sprint(args[i])
+			if equal != (parts[1] == "=") {
+				match = false
+				break
+			}
+		}
+		if match {
+			rows.count++
+		}
+	}
+
+	return rows, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"count"} }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.count < 0 {
+		return io.EOF
+	}
+	dest[0], r.count = r.count, -1
+	return nil
+}
+
+func TestUniqueExists(t *testing.T) {
+
+	t.Parallel()
+
+	db, fake := openFake(t, map[string][]M{
+		"users": {
+			{"id": 1, "username": "alice", "tenant": 1},
+			{"id": 2, "username": "bob", "tenant": 2},
+		},
+		"categories": {
+			{"id": 10, "tenant": 1},
+		},
+	})
+
+	v := New()
+	v.AddColumn("id", "")
+	v.AddColumn("tenant", "")
+	v.AddColumn("username", "").Required("").Unique(db, "users", "username", "", Where("tenant", Ref("tenant")), Ignore("id", Ref("id")))
+	v.AddColumn("category", "").Exists(db, "categories", "id", "", Where("tenant", Ref("tenant")))
+
+	schema := v.Schema()
+
+	cases := []struct {
+		data M
+		rule string
+	}{
+		{M{"tenant": 1, "username": "carol", "category": 10}, ""},
+		{M{"tenant": 2, "username": "alice"}, ""},
+		{M{"tenant": 1, "username": "alice"}, "unique"},
+		{M{"tenant": 1, "id": 1, "username": "alice"}, ""},
+		{M{"tenant": 1, "username": "carol", "category": 11}, "exists"},
+		{M{"tenant": 2, "username": "carol", "category": 10}, "exists"},
+	}
+
+	for i, c := range cases {
+		result, err := schema.ValidateContext(context.Background(), c.data)
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if c.rule == "" && !result.Valid() {
+			t.Error(i, result.FirstError())
+		}
+		if c.rule != "" && (result.Valid() || result.FirstError().GetRule() != c.rule) {
+			t.Error(i, "expected", c.rule, result.FirstError())
+		}
+	}
+
+	fake.Lock()
+	query := fake.queries[0]
+	fake.Unlock()
+	if query != "SELECT COUNT(*) FROM users WHERE username = ? AND tenant = ? AND id <> ?" {
+		t.Error(query)
+	}
+
+	result := schema.Validate(M{"tenant": 1, "username": "alice"})
+	if !errors.Is(result.Errors(), ErrUnique) || result.FirstError().Error() != "username已存在" {
+		t.Error(result.FirstError())
+	}
+}
+
+func TestUniqueExistsQuery(t *testing.T) {
+
+	t.Parallel()
+
+	db, fake := openFake(t, map[string][]M{"users": {{"username": "alice"}}})
+
+	v := New()
+	v.AddColumn("username", "").Unique(db, "users", "username", "", DollarPlaceholders())
+
+	result, err := v.Schema().ValidateContext(context.Background(), M{"username": "bob"})
+	if err != nil || !result.Valid() {
+		t.Fatal(err, result.FirstError())
+	}
+
+	fake.Lock()
+	query := fake.queries[0]
+	fake.err = errors.New("connection refused")
+	fake.Unlock()
+	if query != "SELECT COUNT(*) FROM users WHERE username = $1" {
+		t.Error(query)
+	}
+
+	var lookupErr *LookupError
+	if _, err := v.Schema().ValidateContext(context.Background(), M{"username": "bob"}); !errors.As(err, &lookupErr) || lookupErr.Rule != "unique" {
+		t.Error(err)
+	}
+
+	for _, rule := range []func(r *Rule){
+		func(r *Rule) { r.Unique(db, "users;drop", "email", "") },
+		func(r *Rule) { r.Unique(db, "users", "email or 1", "") },
+		func(r *Rule) { r.Exists(db, "users", "email", "", Where("tenant = 1 or tenant", 1)) },
+		func(r *Rule) { r.Exists(db, "users", "email", "", Ignore("", 1)) },
+	} {
+		v = New()
+		rule(v.AddColumn("email", ""))
+		var buildErr *BuildError
+		if _, err := v.Build(); !errors.As(err, &buildErr) || buildErr.Column != "email" {
+			t.Error("Expected the invalid identifier to fail Build, got", err)
+		}
+	}
+}
@@ -1,5 +1,81 @@
 package govalidate
 
+import (
+	"fmt"
+	"strings"
+)
+
+// ruleError sentinel error of a rule
+type ruleError string
+
+func (e ruleError) Error() string {
+	return "govalidate: " + string(e) + " rule failed"
+}
+
+// ErrRule get the sentinel error of the rule name, errors.Is(err, ErrRule(name)) reports whether err failed the rule
+func ErrRule(name string) error {
+	return ruleError(name)
+}
+
+// Sentinel errors of the built-in rules
+var (
+	ErrRequired            = ErrRule("required")
+	ErrRequiredIf          = ErrRule("requiredIf")
+	ErrRequiredUnless      = ErrRule("requiredUnless")
+	ErrRequiredWith        = ErrRule("requiredWith")
+	ErrRequiredWithAll     = ErrRule("requiredWithAll")
+	ErrRequiredWithout     = ErrRule("requiredWithout")
+	ErrRequiredWithoutAll  = ErrRule("requiredWithoutAll")
+	ErrPresent             = ErrRule("present")
+	ErrFilled              = ErrRule("filled")
+	ErrBool                = ErrRule("bool")
+	ErrAlpha               = ErrRule("alpha")
+	ErrAlphaNumeric        = ErrRule("alphaNumeric")
+	ErrAlphaDash           = ErrRule("alphaDash")
+	ErrBetween             = ErrRule("between")
+	ErrFloat               = ErrRule("float")
+	ErrTimeBefore          = ErrRule("dateBefore")
+	ErrTimeAfter           = ErrRule("dateAfter")
+	ErrEqual               = ErrRule("equal")
+	ErrDifferent           = ErrRule("different")
+	ErrEqualWithColumn     = ErrRule("equalWithColumn")
+	ErrDifferentWithColumn = ErrRule("differentWithColumn")
+	ErrIn                  = ErrRule("in")
+	ErrInteger             = ErrRule("integer")
+	ErrIP                  = ErrRule("ip")
+	ErrNotIn               = ErrRule("notIn")
+	ErrLength              = ErrRule("length")
+	ErrLengthMax           = ErrRule("lengthMax")
+	ErrLengthMin           = ErrRule("lengthMin")
+	ErrBetweenLen          = ErrRule("betweenLen")
+	ErrMax                 = ErrRule("max")
+	ErrMin                 = ErrRule("min")
+	ErrMoney               = ErrRule("money")
+	ErrRegexp              = ErrRule("regexp")
+	ErrNotRegexp           = ErrRule("notRegexp")
+	ErrUsername            = ErrRule("username")
+	ErrHost                = ErrRule("host")
+	ErrEmail               = ErrRule("email")
+	ErrCreditCard          = ErrRule("creditCard")
+	ErrNumeric             = ErrRule("numeric")
+	ErrHexColor            = ErrRule("hexColor")
+	ErrRgbColor            = ErrRule("rgbColor")
+	ErrASCII               = ErrRule("ascii")
+	ErrBase64              = ErrRule("base64")
+	ErrDNSName             = ErrRule("dnsName")
+	ErrURL                 = ErrRule("url")
+	ErrUnique              = ErrRule("unique")
+	ErrExists              = ErrRule("exists")
+	ErrGreaterThan         = ErrRule("greaterThan")
+	ErrLessThan            = ErrRule("lessThan")
+	ErrFormat              = ErrRule("format")
+	ErrAnyOf               = ErrRule("anyOf")
+	ErrOneOf               = ErrRule("oneOf")
+	ErrNot                 = ErrRule("not")
+	ErrType                = ErrRule("type")
+	ErrUnknownField        = ErrRule("unknownField")
+)
+
 // Error struct
 type Error struct {
 	field        string
@@ -8,6 +84,7 @@ type Error struct {
 	rule         string
 	ruleArgs     interface{}
 	errorMessage string
+	err          error
 }
 
 // GetField get field
@@ -35,7 +112,98 @@ func (e *Error) GetErrorMessage() string {
 	return e.errorMessage
 }
 
-// GetRuleArg get rule arg
+// GetRuleArg get rule arg, the Ref arguments are resolved to the values of the referenced columns
 func (e *Error) GetRuleArg() interface{} {
 	return e.ruleArgs
 }
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.errorMessage != "" {
+		return e.errorMessage
+	}
+	return fmt.Sprintf("govalidate: %s failed %s rule", e.field, e.rule)
+}
+
+// Is reports whether the target is the sentinel error of the rule, a failed lookup matches no rule sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(ruleError)
+	return ok && e.err == nil && string(t) == e.rule
+}
+
+// Unwrap get the context error or the *LookupError of a failed lookup, nil for a failed rule
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Errors ordered error collection
+type Errors []*Error
+
+// Error implements the error interface
+func (es Errors) Error() string {
+	messages := make([]string, 0, len(es))
+	for _, e := range es {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap get the errors for errors.Is and errors.As
+func (es Errors) Unwrap() []error {
+	errs := make([]error, 0, len(es))
+	for _, e := range es {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+// Err get the collection as error, nil if empty
+func (es Errors) Err() error {
+	if len(es) == 0 {
+		return nil
+	}
+	return es
+}
+
+// Fields get the failed fields in order
+func (es Errors) Fields() []string {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, e := range es {
+		if !seen[e.field] {
+			seen[e.field] = true
+			fields = append(fields, e.field)
+		}
+	}
+	return fields
+}
+
+// Field get all errors of the field
+func (es Errors) Field(field string) Errors {
+	var res Errors
+	for _, e := range es {
+		if e.field == field {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// First get the first error of the field
+func (es Errors) First(field string) *Error {
+	for _, e := range es {
+		if e.field == field {
+			return e
+		}
+	}
+	return nil
+}
+
+// Group get errors grouped by field
+func (es Errors) Group() map[string]Errors {
+	group := make(map[string]Errors)
+	for _, e := range es {
+		group[e.field] = append(group[e.field], e)
+	}
+	return group
+}
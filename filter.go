@@ -0,0 +1,99 @@
+package govalidate
+
+import (
+	"regexp"
+	"strings"
+)
+
+// FilterFunc transform the value before the later rules of the same column. The rules of the other columns,
+// e.g. Ref arguments, RequiredIf and EqualWithColumn, see the input value, not the filtered one
+type FilterFunc func(value interface{}) interface{}
+
+var (
+	rxpTags   = regexp.MustCompile(`<[^>]*>`)
+	rxpSpaces = regexp.MustCompile(`\s+`)
+)
+
+// Filter 自定义过滤, 本字段之后的规则和 GetData 使用过滤后的值, 其他字段的规则 (如 Ref, RequiredIf) 使用输入值
+func (r *Rule) Filter(fn FilterFunc) *Rule {
+	return r.addFilter("filter", fn)
+}
+
+// Trim 去除首尾空白
+func (r *Rule) Trim() *Rule {
+	return r.addFilter("trim", stringFilter(strings.TrimSpace))
+}
+
+// Lower 转为小写
+func (r *Rule) Lower() *Rule {
+	return r.addFilter("lower", stringFilter(strings.ToLower))
+}
+
+// Upper 转为大写
+func (r *Rule) Upper() *Rule {
+	return r.addFilter("upper", stringFilter(strings.ToUpper))
+}
+
+// StripTags 去除 HTML 标签
+func (r *Rule) StripTags() *Rule {
+	return r.addFilter("stripTags", stringFilter(func(s string) string {
+		return rxpTags.ReplaceAllString(s, "")
+	}))
+}
+
+// CollapseSpaces 连续空白合并为一个空格
+func (r *Rule) CollapseSpaces() *Rule {
+	return r.addFilter("collapseSpaces", stringFilter(func(s string) string {
+		return rxpSpaces.ReplaceAllString(s, " ")
+	}))
+}
+
+// ToInt 转为 int64, 无法转换时保留原值
+func (r *Rule) ToInt() *Rule {
+	return r.addFilter("toInt", func(value interface{}) interface{} {
+		if res, err := ToInt(value); err == nil {
+			return res
+		}
+		return value
+	})
+}
+
+// ToFloat 转为 float64, 无法转换时保留原值
+func (r *Rule) ToFloat() *Rule {
+	return r.addFilter("toFloat", func(value interface{}) interface{} {
+		if res, err := ToFloat(value); err == nil {
+			return res
+		}
+		return value
+	})
+}
+
+// ToBool 转为 bool, 无法转换时保留原值
+func (r *Rule) ToBool() *Rule {
+	return r.addFilter("toBool", func(value interface{}) interface{} {
+		if res, err := ToBoolean(ToString(value)); err == nil {
+			return res
+		}
+		return value
+	})
+}
+
+func (r *Rule) addFilter(name string, fn FilterFunc) *Rule {
+
+	r.item = append(r.item, item{
+		name:   name,
+		filter: fn,
+	})
+
+	return r
+}
+
+// stringFilter apply fn on string values, other values are kept
+func stringFilter(fn func(s string) string) FilterFunc {
+	return func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			return fn(s)
+		}
+		return value
+	}
+}
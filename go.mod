@@ -1,3 +1,3 @@
 module github.com/cium1/govalidate
 
-go 1.13
+go 1.20
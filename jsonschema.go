@@ -0,0 +1,1143 @@
+package govalidate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect the dialect of the exported JSON Schema documents
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchemaRules the extension keyword listing the rules without a JSON Schema equivalent,
+// e.g. custom, conditional and database rules
+const JSONSchemaRules = "x-govalidate-rules"
+
+// patternRules the built-in rules exported as pattern
+var patternRules = map[string]string{
+	"alpha":        Alpha,
+	"alphaNumeric": AlphaNumeric,
+	"alphaDash":    AlphaDash,
+	"username":     Username,
+	"creditCard":   CreditCard,
+	"hexColor":     HexColor,
+	"rgbColor":     RgbColor,
+	"ascii":        ASCII,
+	"base64":       Base64,
+	"money":        Money,
+	"ip":           IP,
+}
+
+// formatRules the built-in rules exported as format
+var formatRules = map[string]string{
+	"email":   "email",
+	"url":     "uri",
+	"host":    "hostname",
+	"dnsName": "hostname",
+}
+
+// stringRules the built-in rules matching numbers and strings, exported as a type or a pattern for strings
+var stringRules = map[string]struct {
+	kind    map[string]interface{}
+	pattern string
+}{
+	"integer": {map[string]interface{}{"type": "integer"}, Int},
+	"float":   {map[string]interface{}{"type": "number"}, Float},
+	"numeric": {map[string]interface{}{"type": "integer", "minimum": 0}, Numeric},
+	"bool":    {map[string]interface{}{"type": "boolean"}, "^(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)$"},
+}
+
+// rxpNotECMA the RE2 syntax which ECMA-262 patterns do not share, e.g. \p{Han}, \x{00A0}, \z and inline flags
+var rxpNotECMA = regexp.MustCompile(`\\[pPAzQC]|\\x\{|\(\?[imsU-]|\(\?P<|\[\[:`)
+
+// JSONSchema export the columns as a JSON Schema (draft 2020-12) document. The nested and wildcard columns
+// become properties and items, the aliases become title, the built-in rules become the matching keywords,
+// the other rules and the patterns using RE2 only syntax, e.g. \p{Han}, are listed in the x-govalidate-rules
+// extension keyword. Required and Present on a nested column also require its parents up to the first wildcard,
+// Required on the elements of an array also sets minItems.
+// The keywords constrain the JSON types they apply to, e.g. minLength only strings, while the rules also accept
+// the string form of numbers
+func (v *Validate) JSONSchema() ([]byte, error) {
+
+	root := map[string]interface{}{
+		"$schema": JSONSchemaDialect,
+		"type":    "object",
+	}
+
+	for _, c := range v.columns {
+
+		parent, node, key := schemaNode(root, c.name)
+
+		if c.alias != "" {
+			node["title"] = c.alias
+		}
+
+		for _, it := range c.rule.item {
+			if !exportItem(root, c.name, parent, node, key, it) {
+				extension(node, it)
+			}
+		}
+	}
+
+	if v.options.unknownMode == UnknownReject {
+		v.closeObjects(root, nil)
+	}
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+// schemaNode get or create the schema of the column, its parent and its key in the parent properties,
+// the key is empty for the items of an array
+func schemaNode(root map[string]interface{}, name string) (map[string]interface{}, map[string]interface{}, string) {
+
+	var parent map[string]interface{}
+	node, key := root, ""
+
+	for _, segment := range strings.Split(name, ".") {
+		parent = node
+		if segment == Wildcard {
+			parent["type"] = "array"
+			node, key = childNode(parent, "items"), ""
+			continue
+		}
+		parent["type"] = "object"
+		node, key = childNode(childNode(parent, "properties"), segment), segment
+	}
+
+	return parent, node, key
+}
+
+func childNode(node map[string]interface{}, key string) map[string]interface{} {
+	child, ok := node[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		node[key] = child
+	}
+	return child
+}
+
+// exportArgs the number of arguments of the built-in rules exported as keywords, -1 for at least one
+var exportArgs = map[string]int{
+	"required": 0, "present": 0, "filled": 0,
+	"length": 1, "lengthMin": 1, "lengthMax": 1, "betweenLen": 2, "minItems": 1,
+	"between": 2, "min": 1, "max": 1, "greaterThan": 1, "lessThan": 1,
+	"in": -1, "notIn": -1, "equal": 1, "different": 1, "regexp": 1, "notRegexp": 1, "type": -1, "format": 1,
+}
+
+// exportItem add the keywords of the rule, false if the rule has no equivalent
+func exportItem(root map[string]interface{}, name string, parent map[string]interface{}, node map[string]interface{}, key string, it item) bool {
+
+	if it.filter != nil || it.nullable {
+		// the rules check the filtered value, the filters do not constrain the input
+		return true
+	}
+
+	if len(it.conditions) > 0 || it.err != nil {
+		return false
+	}
+
+	for _, arg := range it.args {
+		if _, ok := arg.(Ref); ok {
+			return false
+		}
+	}
+
+	args := it.args
+	if n, ok := exportArgs[it.name]; ok && len(args) != n && (n >= 0 || len(args) == 0) {
+		// a Custom or Use rule sharing the name of a built-in rule may take other arguments
+		return false
+	}
+
+	empty := map[string]interface{}{"enum": []interface{}{nil, "", []interface{}{}, map[string]interface{}{}}}
+
+	switch it.name {
+	case "required":
+		if array := requirePath(root, name, parent, key); array != nil {
+			// Required on the elements also rejects the empty array
+			array["minItems"] = 1
+		}
+		set(node, "not", empty)
+	case "present":
+		requirePath(root, name, parent, key)
+	case "filled":
+		set(node, "not", empty)
+	case "length":
+		return setInt(node, args[0], "minLength") && setInt(node, args[0], "maxLength")
+	case "lengthMin":
+		return setInt(node, args[0], "minLength")
+	case "lengthMax":
+		return setInt(node, args[0], "maxLength")
+	case "betweenLen":
+		return setInt(node, args[0], "minLength") && setInt(node, args[1], "maxLength")
+	case "between":
+		return setNumber(node, args[0], "minimum") && setNumber(node, args[1], "maximum")
+	case "min":
+		return setNumber(node, args[0], "minimum")
+	case "max":
+		return setNumber(node, args[0], "maximum")
+	case "greaterThan":
+		return setNumber(node, args[0], "exclusiveMinimum")
+	case "lessThan":
+		return setNumber(node, args[0], "exclusiveMaximum")
+	case "minItems":
+		return setInt(node, args[0], "minItems")
+	case "in":
+		set(node, "enum", jsonArgs(args))
+	case "notIn":
+		set(node, "not", map[string]interface{}{"enum": jsonArgs(args)})
+	case "equal":
+		set(node, "const", jsonArg(args[0]))
+	case "different":
+		set(node, "not", map[string]interface{}{"const": jsonArg(args[0])})
+	case "regexp", "notRegexp":
+		rxp, ok := args[0].(*regexp.Regexp)
+		if !ok || rxpNotECMA.MatchString(rxp.String()) {
+			// the RE2 only patterns are kept for FromJSONSchema in x-govalidate-rules
+			return false
+		}
+		if it.name == "regexp" {
+			set(node, "pattern", rxp.String())
+		} else {
+			set(node, "not", map[string]interface{}{"pattern": rxp.String()})
+		}
+	case "type":
+		if len(args) == 1 {
+			set(node, "type", args[0])
+		} else {
+			set(node, "type", jsonArgs(args))
+		}
+	case "format":
+		set(node, "format", jsonArg(args[0]))
+	default:
+		if pattern, ok := patternRules[it.name]; ok {
+			set(node, "pattern", pattern)
+		} else if format, ok := formatRules[it.name]; ok {
+			set(node, "format", format)
+		} else if s, ok := stringRules[it.name]; ok {
+			set(node, "anyOf", []interface{}{
+				s.kind,
+				map[string]interface{}{"type": "string", "pattern": s.pattern},
+			})
+		} else {
+			return false
+		}
+	}
+
+	return true
+}
+
+// set the keyword, a keyword set by an earlier rule is kept and the new one is added to allOf
+func set(node map[string]interface{}, keyword string, value interface{}) {
+	if _, ok := node[keyword]; !ok {
+		node[keyword] = value
+		return
+	}
+	allOf, _ := node["allOf"].([]interface{})
+	node["allOf"] = append(allOf, map[string]interface{}{keyword: value})
+}
+
+func setInt(node map[string]interface{}, arg interface{}, keyword string) bool {
+	n, err := ToInt(arg)
+	if err != nil {
+		return false
+	}
+	set(node, keyword, n)
+	return true
+}
+
+func setNumber(node map[string]interface{}, arg interface{}, keyword string) bool {
+	n, err := ToFloat(arg)
+	if err != nil {
+		return false
+	}
+	set(node, keyword, n)
+	return true
+}
+
+func requireKey(parent map[string]interface{}, key string) {
+	if key == "" {
+		return
+	}
+	required, _ := parent["required"].([]string)
+	for _, k := range required {
+		if k == key {
+			return
+		}
+	}
+	parent["required"] = append(required, key)
+}
+
+// requirePath require the key of the column and its ancestors up to the first wildcard, a missing ancestor
+// makes the column missing too. Get the schema of the array at the first wildcard, nil without wildcard
+func requirePath(root map[string]interface{}, name string, parent map[string]interface{}, key string) map[string]interface{} {
+
+	requireKey(parent, key)
+
+	node := root
+	for _, segment := range strings.Split(name, ".") {
+		if segment == Wildcard {
+			return node
+		}
+		requireKey(node, segment)
+		node = childNode(childNode(node, "properties"), segment)
+	}
+
+	return nil
+}
+
+// extension list the rule in the extension keyword
+func extension(node map[string]interface{}, it item) {
+
+	rule := map[string]interface{}{"rule": it.name}
+	if len(it.args) > 0 {
+		rule["args"] = jsonArgs(it.args)
+	}
+	if len(it.conditions) > 0 {
+		rule["conditional"] = true
+	}
+
+	rules, _ := node[JSONSchemaRules].([]interface{})
+	node[JSONSchemaRules] = append(rules, rule)
+}
+
+func jsonArgs(args []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		res = append(res, jsonArg(arg))
+	}
+	return res
+}
+
+// jsonArg keep the JSON values, a Ref becomes {"$data": column}, the other values become their string form
+func jsonArg(arg interface{}) interface{} {
+	switch a := arg.(type) {
+	case nil, string, bool, float32, float64, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return a
+	case Ref:
+		return map[string]interface{}{"$data": string(a)}
+	}
+	return display(arg)
+}
+
+// closeObjects forbid the undeclared properties, the keys of AllowKeys pass as patternProperties
+func (v *Validate) closeObjects(node map[string]interface{}, keys []string) {
+
+	if node["type"] == "object" {
+		node["additionalProperties"] = false
+		for _, pattern := range v.options.allowKeys {
+			p := strings.Split(pattern, ".")
+			if len(p) != len(keys)+1 || !matchKeys(p[:len(keys)], keys) {
+				continue
+			}
+			childNode(node, "patternProperties")[globRegexp(p[len(keys)])] = true
+		}
+		properties, _ := node["properties"].(map[string]interface{})
+		for key, child := range properties {
+			v.closeObjects(child.(map[string]interface{}), append(keys[:len(keys):len(keys)], key))
+		}
+	}
+
+	if items, ok := node["items"].(map[string]interface{}); ok {
+		v.closeObjects(items, append(keys[:len(keys):len(keys)], Wildcard))
+	}
+}
+
+// globRegexp translate a path.Match pattern into a regular expression
+func globRegexp(glob string) string {
+
+	var b strings.Builder
+	b.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '\\':
+			if i+1 < len(glob) {
+				i++
+				b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+			}
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			b.WriteString(glob[i : i+end+1])
+			i += end
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	b.WriteString("$")
+
+	return b.String()
+}
+
+// JSONSchemaError an unsupported or invalid keyword of a JSON Schema document
+type JSONSchemaError struct {
+	Pointer string // JSON pointer of the schema, e.g. /properties/age
+	Keyword string
+	Reason  string
+}
+
+func (e *JSONSchemaError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	if e.Keyword == "" {
+		return fmt.Sprintf("govalidate: json schema %s: %s", pointer, e.Reason)
+	}
+	return fmt.Sprintf("govalidate: json schema %s: keyword %q %s", pointer, e.Keyword, e.Reason)
+}
+
+// schemaKeywords the supported keywords in the order their rules are added, the annotations add no rule
+var schemaKeywords = []string{
+	"$schema", "$id", "$comment", "title", "description", "examples", "default", "deprecated", "readOnly",
+	"writeOnly", "type", "enum", "const", "format", "pattern", "minLength", "maxLength",
+	"minItems", "minimum", "exclusiveMinimum", "maximum", "exclusiveMaximum", "allOf", "anyOf", "oneOf", "not",
+	"properties", "required", "additionalProperties", "items", JSONSchemaRules,
+}
+
+var supportedKeywords = func() map[string]bool {
+	supported := make(map[string]bool, len(schemaKeywords))
+	for _, keyword := range schemaKeywords {
+		supported[keyword] = true
+	}
+	return supported
+}()
+
+// rootKeywords the keywords supported by the root schema, which describes the data itself
+var rootKeywords = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "examples": true,
+	"type": true, "allOf": true, "properties": true, "required": true, "additionalProperties": true,
+}
+
+// boundRules the built-in rules of the numeric bounds
+var boundRules = map[string]struct {
+	name string
+	fn   Func
+}{
+	"minimum":          {"min", (&Validate{}).min},
+	"maximum":          {"max", (&Validate{}).max},
+	"exclusiveMinimum": {"greaterThan", (&Validate{}).greaterThan},
+	"exclusiveMaximum": {"lessThan", (&Validate{}).lessThan},
+}
+
+// schemaFormats the supported formats and the built-in rules checking them, the others use the format rule
+var schemaFormats = map[string]string{
+	"email":     "email",
+	"uri":       "url",
+	"hostname":  "dnsName",
+	"ipv4":      "format",
+	"ipv6":      "format",
+	"date-time": "format",
+	"date":      "format",
+	"time":      "format",
+	"uuid":      "format",
+}
+
+var rxpUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// importer build the columns of a JSON Schema document
+type importer struct {
+	v      *Validate
+	strict bool     // an object forbids additional properties
+	open   []string // the objects allowing additional properties
+}
+
+// FromJSONSchema build the columns from a JSON Schema (draft 2020-12) document: properties and items become
+// nested and wildcard columns, title becomes the alias and the keywords become the rules, which apply to the
+// JSON types of the keywords like in JSON Schema. The rules of x-govalidate-rules are added by name.
+// default is an annotation like in JSON Schema and fills no missing value, add Rule.Default for that.
+// The unsupported keywords, e.g. $ref, are reported as *JSONSchemaError
+func FromJSONSchema(document []byte) (*Validate, error) {
+
+	node, err := decodeNode(document, "")
+	if err != nil {
+		return nil, err
+	}
+
+	im := &importer{v: New()}
+	if err := im.compile("", "", node); err != nil {
+		return nil, err
+	}
+
+	return im.finish(), nil
+}
+
+// finish reject the additional properties if an object forbids them, the keys of the other objects pass through
+func (im *importer) finish() *Validate {
+
+	if im.strict {
+		im.v.SetUnknownMode(UnknownReject)
+		for _, path := range im.open {
+			im.v.AllowKeys(join(path, "*"))
+		}
+	}
+
+	return im.v
+}
+
+// compile add the rules of the schema node to the column of the path
+func (im *importer) compile(path string, pointer string, node map[string]json.RawMessage) error {
+
+	var unsupported []string
+	for keyword := range node {
+		if !supportedKeywords[keyword] {
+			unsupported = append(unsupported, keyword)
+		}
+	}
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return &JSONSchemaError{Pointer: pointer, Keyword: unsupported[0], Reason: "is not supported"}
+	}
+
+	var alias string
+	if raw, ok := node["title"]; ok {
+		if err := json.Unmarshal(raw, &alias); err != nil {
+			return &JSONSchemaError{Pointer: pointer, Keyword: "title", Reason: "must be a string"}
+		}
+	}
+
+	var r *Rule
+	if path != "" {
+		r = im.v.AddColumn(path, alias)
+	}
+
+	for _, keyword := range schemaKeywords {
+
+		raw, ok := node[keyword]
+		if !ok {
+			continue
+		}
+
+		fail := func(reason string) error {
+			return &JSONSchemaError{Pointer: pointer, Keyword: keyword, Reason: reason}
+		}
+
+		if path == "" && !rootKeywords[keyword] {
+			return fail("is not supported by the root schema")
+		}
+
+		var err error
+
+		switch keyword {
+		case "type":
+			if path == "" {
+				var kind string
+				if json.Unmarshal(raw, &kind) != nil || kind != "object" {
+					return fail("must be object for the root schema")
+				}
+				continue
+			}
+			err = schemaType(r, raw)
+		case "enum":
+			var values []interface{}
+			if values, err = decodeList(raw); err == nil && len(values) == 0 {
+				err = fmt.Errorf("must not be empty")
+			}
+			if err == nil {
+				r.Custom("in", matchValues, "", values...)
+			}
+		case "const":
+			var value interface{}
+			if value, err = decodeValue(raw); err == nil {
+				r.Custom("equal", matchValues, "", value)
+			}
+		case "format":
+			err = schemaFormat(r, raw)
+		case "pattern":
+			var pattern string
+			if err = json.Unmarshal(raw, &pattern); err == nil {
+				var rxp *regexp.Regexp
+				if rxp, err = regexp.Compile(pattern); err == nil {
+					r.Custom("regexp", onlyKind("string", (&Validate{}).regexp), "", rxp)
+				}
+			}
+		case "minLength", "maxLength":
+			var n int64
+			if err = json.Unmarshal(raw, &n); err == nil && n < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+			if err == nil {
+				fn, name := (&Validate{}).lengthMin, "lengthMin"
+				if keyword == "maxLength" {
+					fn, name = (&Validate{}).lengthMax, "lengthMax"
+				}
+				r.Custom(name, onlyKind("string", fn), "", n)
+			}
+		case "minItems":
+			var n int64
+			if err = json.Unmarshal(raw, &n); err == nil && n < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+			if err == nil {
+				r.Custom("minItems", onlyKind("array", minItems), "", n)
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			var n interface{}
+			if n, err = decodeValue(raw); err == nil && !isNumber(n) {
+				err = fmt.Errorf("must be a number")
+			}
+			if err == nil {
+				bound := boundRules[keyword]
+				r.Custom(bound.name, onlyKind("number", bound.fn), "", n)
+			}
+		case "allOf":
+			err = im.each(raw, pointer+"/allOf", func(pointer string, node map[string]json.RawMessage) error {
+				return im.compile(path, pointer, node)
+			})
+		case "anyOf", "oneOf", "not":
+			err = im.combine(r, keyword, raw, pointer)
+		case "properties":
+			err = im.properties(path, pointer, raw)
+		case "required":
+			var keys []string
+			if err = json.Unmarshal(raw, &keys); err == nil {
+				for _, key := range keys {
+					im.v.AddColumn(join(path, key), "").Custom("present", presentInObject, "")
+				}
+			}
+		case "additionalProperties":
+			var allowed bool
+			if json.Unmarshal(raw, &allowed) != nil {
+				return fail("must be a boolean, schemas are not supported")
+			}
+			if !allowed {
+				im.strict = true
+			}
+		case "items":
+			var items map[string]json.RawMessage
+			if items, err = decodeNode(raw, pointer+"/items"); err == nil {
+				err = im.compile(join(path, Wildcard), pointer+"/items", items)
+			}
+		case JSONSchemaRules:
+			err = extensionRules(r, raw)
+		}
+
+		if err != nil {
+			if _, ok := err.(*JSONSchemaError); ok {
+				return err
+			}
+			return fail(err.Error())
+		}
+	}
+
+	if _, ok := node["properties"]; ok {
+		if raw, ok := node["additionalProperties"]; !ok || string(bytes.TrimSpace(raw)) != "false" {
+			im.open = append(im.open, path)
+		}
+	}
+
+	return nil
+}
+
+// schemaType add the type rule, the integer type accepts integral numbers
+func schemaType(r *Rule, raw json.RawMessage) error {
+
+	var types []string
+	var kind string
+	if err := json.Unmarshal(raw, &kind); err == nil {
+		types = []string{kind}
+	} else if err := json.Unmarshal(raw, &types); err != nil {
+		return fmt.Errorf("must be a string or an array of strings")
+	}
+
+	args := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		switch t {
+		case "string", "number", "integer", "boolean", "object", "array", "null":
+			args = append(args, t)
+		default:
+			return fmt.Errorf("has an unknown type %q", t)
+		}
+	}
+
+	r.Custom("type", func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, ok := Lookup(data, column)
+		if !ok {
+			return true
+		}
+		kind := jsonType(value)
+		for _, t := range args {
+			if t == kind || (t == "number" && kind == "integer") {
+				return true
+			}
+		}
+		return false
+	}, "", args...)
+
+	return nil
+}
+
+func schemaFormat(r *Rule, raw json.RawMessage) error {
+
+	var format string
+	if err := json.Unmarshal(raw, &format); err != nil {
+		return err
+	}
+
+	name, ok := schemaFormats[format]
+	if !ok {
+		return fmt.Errorf("has an unsupported format %q", format)
+	}
+
+	var fn Func
+	switch name {
+	case "email":
+		fn = (&Validate{}).email
+	case "url":
+		fn = (&Validate{}).url
+	case "dnsName":
+		fn = (&Validate{}).dnsName
+	default:
+		fn = func(data map[string]interface{}, column string, args ...interface{}) bool {
+			value, _ := Lookup(data, column)
+			s := ToString(value)
+			var err error
+			switch format {
+			case "date-time":
+				_, err = time.Parse(time.RFC3339, s)
+			case "date":
+				_, err = time.Parse("2006-01-02", s)
+			case "time":
+				_, err = time.Parse("15:04:05Z07:00", s)
+			case "uuid":
+				return rxpUUID.MatchString(s)
+			case "ipv4":
+				ip := net.ParseIP(s)
+				return ip != nil && ip.To4() != nil
+			case "ipv6":
+				ip := net.ParseIP(s)
+				return ip != nil && ip.To4() == nil
+			}
+			return err == nil
+		}
+		r.Custom(name, onlyKind("string", fn), "", format)
+		return nil
+	}
+
+	r.Custom(name, onlyKind("string", fn), "")
+
+	return nil
+}
+
+// combine add the anyOf, oneOf or not rule, the subschemas are compiled into schemas validating {"value": value}
+func (im *importer) combine(r *Rule, keyword string, raw json.RawMessage, pointer string) error {
+
+	var schemas []*Schema
+
+	compile := func(pointer string, node map[string]json.RawMessage) error {
+		sub := &importer{v: New()}
+		if err := sub.compile("value", pointer, node); err != nil {
+			return err
+		}
+		if errs := sub.v.misconfigured(); len(errs) > 0 {
+			return errs
+		}
+		schemas = append(schemas, sub.finish().Schema())
+		return nil
+	}
+
+	var err error
+	if keyword == "not" {
+		var node map[string]json.RawMessage
+		if node, err = decodeNode(raw, pointer+"/not"); err == nil {
+			err = compile(pointer+"/not", node)
+		}
+	} else {
+		err = im.each(raw, pointer+"/"+keyword, compile)
+	}
+	if err != nil {
+		return err
+	}
+
+	r.CustomContext(keyword, func(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error) {
+
+		value, ok := Lookup(data, column)
+		if !ok {
+			return true, nil
+		}
+
+		matched := 0
+		for _, schema := range schemas {
+			result, err := schema.ValidateContext(ctx, M{"value": value})
+			if err != nil {
+				return false, err
+			}
+			if result.Valid() {
+				matched++
+			}
+			if keyword == "anyOf" && matched > 0 {
+				break
+			}
+		}
+
+		switch keyword {
+		case "anyOf":
+			return matched > 0, nil
+		case "oneOf":
+			return matched == 1, nil
+		}
+		return matched == 0, nil
+	}, "")
+
+	return nil
+}
+
+// properties compile the properties in the document order
+func (im *importer) properties(path string, pointer string, raw json.RawMessage) error {
+
+	keys, err := objectKeys(raw)
+	if err != nil {
+		return err
+	}
+
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &properties); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		child := pointer + "/properties/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+		node, err := decodeNode(properties[key], child)
+		if err != nil {
+			return err
+		}
+		if err := im.compile(join(path, key), child, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rules add the rules of the extension keyword, the registered rules by name and the built-in rules by their rule string
+func extensionRules(r *Rule, raw json.RawMessage) error {
+
+	var rules []struct {
+		Rule        string        `json:"rule"`
+		Args        []interface{} `json:"args"`
+		Conditional bool          `json:"conditional"`
+	}
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+
+		if rule.Conditional {
+			return fmt.Errorf("has the conditional rule %s", rule.Rule)
+		}
+
+		args := make([]interface{}, 0, len(rule.Args))
+		for _, arg := range rule.Args {
+			if ref, ok := arg.(map[string]interface{}); ok && len(ref) == 1 && ref["$data"] != nil {
+				arg = Ref(ToString(ref["$data"]))
+			}
+			args = append(args, normalizeValue(arg))
+		}
+
+		spec, ok := ruleSpecs[rule.Rule]
+		if !ok {
+			r.Use(rule.Rule, "", args...)
+			continue
+		}
+
+		list := make([]string, 0, len(args))
+		for _, arg := range args {
+			if ref, ok := arg.(Ref); ok {
+				if !spec.refs {
+					return fmt.Errorf("has the rule %s with a column reference", rule.Rule)
+				}
+				arg = RefPrefix + string(ref)
+			}
+			list = append(list, ToString(arg))
+		}
+		if spec.args >= 0 && len(list) != spec.args {
+			return fmt.Errorf("has the rule %s which expects %s", rule.Rule, argCount(spec.args))
+		}
+		if err := spec.build(r, list, ""); err != nil {
+			return fmt.Errorf("has the rule %s: %v", rule.Rule, err)
+		}
+	}
+
+	return nil
+}
+
+// each compile the schemas of the array keyword
+func (im *importer) each(raw json.RawMessage, pointer string, fn func(pointer string, node map[string]json.RawMessage) error) error {
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err != nil || len(list) == 0 {
+		return fmt.Errorf("must be a non-empty array of schemas")
+	}
+
+	for i, raw := range list {
+		child := pointer + "/" + strconv.Itoa(i)
+		node, err := decodeNode(raw, child)
+		if err != nil {
+			return err
+		}
+		if err := fn(child, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeNode decode a schema, the true schema is the empty one
+func decodeNode(raw json.RawMessage, pointer string) (map[string]json.RawMessage, error) {
+
+	var allowed bool
+	if err := json.Unmarshal(raw, &allowed); err == nil {
+		if !allowed {
+			return nil, &JSONSchemaError{Pointer: pointer, Reason: "the false schema is not supported"}
+		}
+		return map[string]json.RawMessage{}, nil
+	}
+
+	var node map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &node); err != nil {
+		return nil, &JSONSchemaError{Pointer: pointer, Reason: "is not a schema: " + err.Error()}
+	}
+
+	return node, nil
+}
+
+// objectKeys get the keys of the JSON object in the document order
+func objectKeys(raw json.RawMessage) ([]string, error) {
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return nil, fmt.Errorf("must be an object")
+	}
+
+	var keys []string
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		keys = append(keys, token.(string))
+	}
+
+	return keys, nil
+}
+
+// decodeValue decode a JSON value, the integral numbers become int64 and the others float64
+func decodeValue(raw json.RawMessage) (interface{}, error) {
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return normalizeValue(value), nil
+}
+
+func decodeList(raw json.RawMessage) ([]interface{}, error) {
+	value, err := decodeValue(raw)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be an array")
+	}
+	return list, nil
+}
+
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeValue(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = normalizeValue(v[k])
+		}
+	}
+	return value
+}
+
+// jsonType get the JSON type of the value, the integral numbers are integer
+func jsonType(value interface{}) string {
+
+	if n, ok := value.(json.Number); ok {
+		if _, err := n.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	}
+
+	val := indirect(reflect.ValueOf(value))
+
+	switch val.Kind() {
+	case reflect.Invalid:
+		return "null"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		if f := val.Float(); f == math.Trunc(f) && !math.IsInf(f, 0) {
+			return "integer"
+		}
+		return "number"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+
+	return ""
+}
+
+// presentInObject like present, the key is only required in an object like in JSON Schema
+func presentInObject(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if i := strings.LastIndex(column, "."); i >= 0 {
+		if parent, ok := Lookup(data, column[:i]); !ok || jsonType(parent) != "object" {
+			return true
+		}
+	}
+
+	_, ok := Lookup(data, column)
+	return ok
+}
+
+// minItems check the array has at least args[0] elements like in JSON Schema
+func minItems(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	value, ok := Lookup(data, column)
+	if !ok || len(args) < 1 {
+		return !ok
+	}
+
+	n, err := lengthArg(args[0])
+	if err != nil {
+		return false
+	}
+
+	return int64(indirect(reflect.ValueOf(value)).Len()) >= n
+}
+
+func isNumber(value interface{}) bool {
+	t := jsonType(value)
+	return t == "integer" || t == "number"
+}
+
+// matchValues match the value against the arguments like JSON Schema enum and const, the numbers are
+// compared by value, the arrays and objects item by item
+func matchValues(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	value, ok := Lookup(data, column)
+	if !ok {
+		return true
+	}
+
+	for _, arg := range args {
+		if jsonEqual(value, arg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func jsonEqual(a interface{}, b interface{}) bool {
+
+	if isNumber(a) && isNumber(b) {
+		x, _ := ToFloat(a)
+		y, _ := ToFloat(b)
+		return x == y
+	}
+
+	ta, tb := jsonType(a), jsonType(b)
+	if ta != tb {
+		return false
+	}
+
+	switch ta {
+	case "array":
+		x, y := indirect(reflect.ValueOf(a)), indirect(reflect.ValueOf(b))
+		if x.Len() != y.Len() {
+			return false
+		}
+		for i := 0; i < x.Len(); i++ {
+			if !jsonEqual(x.Index(i).Interface(), y.Index(i).Interface()) {
+				return false
+			}
+		}
+		return true
+	case "object":
+		x, y := childKeys(a), childKeys(b)
+		if len(x) != len(y) {
+			return false
+		}
+		for i, key := range x {
+			if key != y[i] {
+				return false
+			}
+			u, _ := child(a, key)
+			v, _ := child(b, key)
+			if !jsonEqual(u, v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// onlyKind apply fn to the values of the JSON type, number includes integer, the other values pass like in JSON Schema
+func onlyKind(kind string, fn Func) Func {
+	return func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, ok := Lookup(data, column)
+		if !ok {
+			return true
+		}
+		if t := jsonType(value); t != kind && !(kind == "number" && t == "integer") {
+			return true
+		}
+		return fn(data, column, args...)
+	}
+}
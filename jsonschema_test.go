@@ -0,0 +1,408 @@
+package govalidate
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONSchema(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "登录账户").Trim().Required("").AlphaNumeric("").BetweenLen(4, 16, "")
+	v.AddColumn("age", "").Integer("").Between(1, 150, "").Max(200, "")
+	v.AddColumn("status", "").In([]interface{}{"a", "b"}, "")
+	v.AddColumn("code", "").Numeric("")
+	v.AddColumn("email", "").Email("").NotIn([]interface{}{"root@example.com"}, "")
+	v.AddColumn("address.city", "城市").Present("").Regexp(`^\p{Han}+$`, "")
+	v.AddColumn("items.*.sku", "").Custom("sku", func(data map[string]interface{}, column string, args ...interface{}) bool { return true }, "", "v1")
+	v.AddColumn("quantity", "").Max(Ref("stock"), "").When(IfPresent("stock"), func(r *Rule) { r.Min(1, "") })
+	v.AddColumn("tags.*", "").Required("")
+	v.SetUnknownMode(UnknownReject).AllowKeys("x_*", "address.meta")
+
+	expected := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"patternProperties": {"^x_.*$": true},
+		"required": ["username", "address", "tags"],
+		"properties": {
+			"username": {
+				"title": "登录账户",
+				"not": {"enum": [null, "", [], {}]},
+				"pattern": "^[a-zA-Z0-9]+$",
+				"minLength": 4,
+				"maxLength": 16
+			},
+			"age": {
+				"anyOf": [{"type": "integer"}, {"type": "string", "pattern": "^(?:[-+]?(?:0|[1-9][0-9]*))$"}],
+				"minimum": 1,
+				"maximum": 150,
+				"allOf": [{"maximum": 200}]
+			},
+			"status": {"enum": ["a", "b"]},
+			"code": {"anyOf": [{"type": "integer", "minimum": 0}, {"type": "string", "pattern": "^[0-9]+$"}]},
+			"email": {"format": "email", "not": {"enum": ["root@example.com"]}},
+			"address": {
+				"type": "object",
+				"additionalProperties": false,
+				"patternProperties": {"^meta$": true},
+				"required": ["city"],
+				"properties": {"city": {"title": "城市", "x-govalidate-rules": [{"rule": "regexp", "args": ["^\\p{Han}+$"]}]}}
+			},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"additionalProperties": false,
+					"properties": {"sku": {"x-govalidate-rules": [{"rule": "sku", "args": ["v1"]}]}}
+				}
+			},
+			"quantity": {
+				"x-govalidate-rules": [
+					{"rule": "max", "args": [{"$data": "stock"}]},
+					{"rule": "min", "args": [1], "conditional": true}
+				]
+			},
+			"tags": {"type": "array", "minItems": 1, "items": {"not": {"enum": [null, "", [], {}]}}}
+		}
+	}`
+
+	b, err := v.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unexpected JSON Schema %s", b)
+	}
+
+	v = New()
+	v.AddColumn("server", "").IP("")
+
+	if b, err = v.JSONSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Properties map[string]map[string]interface{}
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if pattern := doc.Properties["server"]["pattern"]; pattern != IP {
+		t.Errorf("Expected the ip rule to export its pattern, got %s", b)
+	}
+
+	// the rules sharing a built-in name with other arguments are extension rules
+	fn := func(data map[string]interface{}, column string, args ...interface{}) bool { return true }
+	v = New()
+	v.AddColumn("a", "").Custom("equal", fn, "")
+	v.AddColumn("b", "").Custom("between", fn, "", 1)
+	v.AddColumn("c", "").Use("in", "")
+
+	if b, err = v.JSONSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, column := range []string{"a", "b", "c"} {
+		if rules, ok := doc.Properties[column][JSONSchemaRules].([]interface{}); !ok || len(rules) != 1 || len(doc.Properties[column]) != 1 {
+			t.Errorf("Expected %s to be an extension rule, got %s", column, b)
+		}
+	}
+}
+
+func TestFromJSONSchema(t *testing.T) {
+
+	t.Parallel()
+
+	document := `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["username", "items"],
+		"additionalProperties": false,
+		"properties": {
+			"username": {"title": "登录账户", "type": "string", "minLength": 4, "maxLength": 8, "pattern": "^[a-z0-9]+$"},
+			"age": {"type": "integer", "minimum": 1, "exclusiveMaximum": 150},
+			"status": {"enum": ["a", "b", 1]},
+			"version": {"const": 2},
+			"email": {"type": ["string", "null"], "format": "email"},
+			"created_at": {"format": "date-time"},
+			"server_v4": {"format": "ipv4"},
+			"server_v6": {"format": "ipv6"},
+			"page_size": {"type": "integer", "default": 20},
+			"address": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string", "minLength": 1}}
+			},
+			"items": {
+				"type": "array",
+				"items": {
+					"type": "object",
+					"required": ["sku"],
+					"additionalProperties": false,
+					"properties": {"sku": {"type": "string"}, "quantity": {"type": "integer", "minimum": 1}}
+				}
+			},
+			"contact": {"anyOf": [{"type": "string", "format": "email"}, {"type": "string", "pattern": "^1[0-9]{10}$"}]},
+			"code": {"oneOf": [{"type": "integer"}, {"type": "number", "minimum": 100}]},
+			"nickname": {"allOf": [{"type": "string"}, {"maxLength": 4}], "not": {"const": "root"}}
+		}
+	}`
+
+	v, err := FromJSONSchema([]byte(document))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := v.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() M {
+		return M{"username": "tester", "items": []interface{}{M{"sku": "s1", "quantity": 2}}}
+	}
+
+	var tests = []*struct {
+		key   string
+		value interface{}
+		field string
+		rule  string
+	}{
+		{"", nil, "", ""},
+		{"age", 18, "", ""},
+		{"age", 18.0, "", ""},
+		{"age", 18.5, "age", "type"},
+		{"age", "18", "age", "type"},
+		{"age", 0, "age", "min"},
+		{"age", 150, "age", "lessThan"},
+		{"status", "b", "", ""},
+		{"status", 1.0, "", ""},
+		{"status", "1", "status", "in"},
+		{"version", 2, "", ""},
+		{"version", 3, "version", "equal"},
+		{"email", nil, "", ""},
+		{"email", "a@b.c", "", ""},
+		{"email", "ab.c", "email", "email"},
+		{"email", 1, "email", "type"},
+		{"created_at", "2026-10-18T10:00:00Z", "", ""},
+		{"created_at", "2026-10-18", "created_at", "format"},
+		{"created_at", 1, "", ""},
+		{"server_v4", "1.2.3.4", "", ""},
+		{"server_v4", "256.1.1.1", "server_v4", "format"},
+		{"server_v4", "::1", "server_v4", "format"},
+		{"server_v4", "x::", "server_v4", "format"},
+		{"server_v6", "::1", "", ""},
+		{"server_v6", "2001:db8::1", "", ""},
+		{"server_v6", "x::", "server_v6", "format"},
+		{"server_v6", "1.2.3.4", "server_v6", "format"},
+		{"address", M{"city": "上海"}, "", ""},
+		{"address", M{"city": "上海", "zip": "200000"}, "", ""},
+		{"address", M{}, "address.city", "present"},
+		{"address", "上海", "address", "type"},
+		{"items", []interface{}{M{"sku": "s1", "quantity": 0}}, "items.0.quantity", "min"},
+		{"items", []interface{}{M{"sku": "s1"}, M{}}, "items.1.sku", "present"},
+		{"items", []interface{}{M{"sku": "s1", "price": 1}}, "items.0.price", "unknownField"},
+		{"contact", "a@b.c", "", ""},
+		{"contact", "13800000000", "", ""},
+		{"contact", "1380000", "contact", "anyOf"},
+		{"code", 7, "", ""},
+		{"code", 100.5, "", ""},
+		{"code", 200, "code", "oneOf"},
+		{"code", 7.5, "code", "oneOf"},
+		{"nickname", "tom", "", ""},
+		{"nickname", "tommy", "nickname", "lengthMax"},
+		{"nickname", "root", "nickname", "not"},
+		{"username", "abc", "username", "lengthMin"},
+		{"username", "Tester", "username", "regexp"},
+		{"extra", 1, "extra", "unknownField"},
+	}
+
+	for _, test := range tests {
+
+		data := valid()
+		if test.key != "" {
+			data[test.key] = test.value
+		}
+
+		result := schema.Validate(data)
+		if test.rule == "" && !result.Valid() {
+			t.Errorf("Expected %s=%v to pass, got %v", test.key, test.value, result.FirstError())
+			continue
+		}
+		if test.rule != "" && (result.Valid() || result.FirstError().GetField() != test.field || result.FirstError().GetRule() != test.rule) {
+			t.Errorf("Expected %s=%v to fail %s %s, got %v", test.key, test.value, test.field, test.rule, result.FirstError())
+		}
+	}
+
+	// the required keys of the elements do not require the optional array
+	optional, err := FromJSONSchema([]byte(`{"properties": {"items": {"type": "array", "items": {"required": ["sku"]}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !optional.Validate(M{}) || optional.Validate(M{"items": []interface{}{M{}}}) {
+		t.Errorf("Expected only the elements to require sku, got %v", optional.Error())
+	}
+
+	result := schema.Validate(M{"items": []interface{}{}})
+	if result.FirstError().GetRule() != "present" || result.FirstError().Error() != "登录账户字段必须存在" {
+		t.Errorf("Expected the title as alias, got %v", result.FirstError())
+	}
+
+	// default is an annotation, it neither fills the value nor satisfies required
+	if result := schema.Validate(valid()); result.GetData()["page_size"] != nil {
+		t.Errorf("Expected no default, got %v", result.GetData())
+	}
+
+	required, err := FromJSONSchema([]byte(`{"required": ["a"], "properties": {"a": {"default": 1}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if required.Validate(M{}) || required.Error().GetField() != "a" {
+		t.Errorf("Expected the required key with a default to fail, got %v", required.GetData())
+	}
+}
+
+func TestFromJSONSchemaRoundTrip(t *testing.T) {
+
+	t.Parallel()
+
+	sku := func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, ok := Lookup(data, column)
+		return !ok || strings.HasPrefix(ToString(value), ToString(args[0]))
+	}
+
+	v := New()
+	v.RegisterRule("sku", sku)
+	v.AddColumn("username", "登录账户").Required("").AlphaNumeric("").BetweenLen(4, 16, "")
+	v.AddColumn("age", "").Integer("").Between(1, 150, "").GreaterThan(0, "")
+	v.AddColumn("sku", "").Custom("sku", nil, "", "SKU-").Regexp(`-\d+$`, "")
+	v.AddColumn("status", "").In([]interface{}{"a", "b"}, "")
+	v.AddColumn("address.city", "").Required("").Regexp(`^\p{Han}+$`, "")
+	v.AddColumn("code", "").Numeric("")
+
+	b, err := v.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := FromJSONSchema(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported.RegisterRule("sku", sku)
+
+	for _, data := range []M{
+		{"username": "tester", "age": 18, "sku": "SKU-12", "status": "a", "address": M{"city": "上海"}, "code": "12"},
+		{"username": "te", "age": 18, "address": M{"city": "上海"}},
+		{"username": "tester", "age": 200, "address": M{"city": "上海"}},
+		{"username": "tester", "sku": "ABC-12", "address": M{"city": "上海"}},
+		{"username": "tester", "sku": "SKU-AB", "address": M{"city": "上海"}},
+		{"username": "tester", "status": "c", "address": M{"city": "上海"}},
+		{"username": "tester", "address": M{"city": "Shanghai"}},
+		{"username": "tester", "address": M{"city": "上海"}, "code": 12},
+		{"username": "tester", "address": M{"city": "上海"}, "code": -1},
+	} {
+		want, got := v.Validate(data), imported.Validate(data)
+		if want != got || (!want && v.Error().GetField() != imported.Error().GetField()) {
+			t.Errorf("Expected %v to be %v %v, got %v %v", data, want, v.Error(), got, imported.Error())
+		}
+	}
+
+	// the missing parent of a required column fails as the parent in the imported schema
+	if data := (M{"username": "tester"}); v.Validate(data) || imported.Validate(data) || imported.Error().GetField() != "address" {
+		t.Errorf("Expected the missing parent to fail, got %v", imported.Error())
+	}
+
+	// Required on the elements rejects the missing and the empty array in both
+	v = New()
+	v.AddColumn("items.*.sku", "").Required("")
+
+	if b, err = v.JSONSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if imported, err = FromJSONSchema(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range []M{
+		{"items": []interface{}{M{"sku": "s1"}}},
+		{"items": []interface{}{M{"sku": ""}}},
+		{"items": []interface{}{}},
+		{},
+	} {
+		want, got := v.Validate(data), imported.Validate(data)
+		if want != got || (!want && v.Error().GetField() != imported.Error().GetField()) {
+			t.Errorf("Expected %v to be %v %v, got %v %v", data, want, v.Error(), got, imported.Error())
+		}
+	}
+
+	// the Ref bounds are kept as extension rules
+	v = New()
+	v.AddColumn("quantity", "").Between(1, Ref("stock"), "")
+
+	if b, err = v.JSONSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if imported, err = FromJSONSchema(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range []M{{"quantity": 3, "stock": 5}, {"quantity": 6, "stock": 5}, {"quantity": 0, "stock": 5}} {
+		if want, got := v.Validate(data), imported.Validate(data); want != got {
+			t.Errorf("Expected %v to be %v, got %v", data, want, got)
+		}
+	}
+}
+
+func TestFromJSONSchemaErrors(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		document string
+		pointer  string
+		keyword  string
+	}{
+		{`{"type": "object", "$ref": "#/$defs/user"}`, "", "$ref"},
+		{`{"type": "array"}`, "", "type"},
+		{`{"minLength": 1}`, "", "minLength"},
+		{`{"properties": {"age": {"type": "int"}}}`, "/properties/age", "type"},
+		{`{"properties": {"age": {"minimum": "1"}}}`, "/properties/age", "minimum"},
+		{`{"properties": {"name": {"minLength": -1}}}`, "/properties/name", "minLength"},
+		{`{"properties": {"tags": {"minItems": -1}}}`, "/properties/tags", "minItems"},
+		{`{"properties": {"name": {"pattern": "[a-"}}}`, "/properties/name", "pattern"},
+		{`{"properties": {"name": {"format": "iri"}}}`, "/properties/name", "format"},
+		{`{"properties": {"name": {"enum": []}}}`, "/properties/name", "enum"},
+		{`{"properties": {"tags": {"items": [{"type": "string"}]}}}`, "/properties/tags/items", ""},
+		{`{"properties": {"tags": {"prefixItems": [{"type": "string"}]}}}`, "/properties/tags", "prefixItems"},
+		{`{"properties": {"a/b": {"anyOf": [{"type": "string", "dependentRequired": {}}]}}}`, "/properties/a~1b/anyOf/0", "dependentRequired"},
+		{`{"properties": {"meta": {"additionalProperties": {"type": "string"}}}}`, "/properties/meta", "additionalProperties"},
+		{`{"properties": {"name": false}}`, "/properties/name", ""},
+		{`{"properties": {"name": {"x-govalidate-rules": [{"rule": "in", "args": [{"$data": "names"}]}]}}}`, "/properties/name", "x-govalidate-rules"},
+	}
+
+	for _, test := range tests {
+		_, err := FromJSONSchema([]byte(test.document))
+		var se *JSONSchemaError
+		if !errors.As(err, &se) || se.Pointer != test.pointer || se.Keyword != test.keyword {
+			t.Errorf("Expected error of %s %s for %s, got %v", test.pointer, test.keyword, test.document, err)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package govalidate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Locales of the bundled catalogs
+const (
+	LocaleZhCN = "zh-CN"
+	LocaleEn   = "en"
+)
+
+var catalogs = struct {
+	sync.RWMutex
+	defaultLocale string
+	messages      map[string]map[string]string
+}{
+	defaultLocale: LocaleZhCN,
+	messages: map[string]map[string]string{
+		LocaleZhCN: {
+			"required":            "{alias}是必须的",
+			"requiredIf":          "{other}为{values}时{alias}是必须的",
+			"requiredUnless":      "除非{other}为{values}, 否则{alias}是必须的",
+			"requiredWith":        "{others}存在时{alias}是必须的",
+			"requiredWithAll":     "{others}都存在时{alias}是必须的",
+			"requiredWithout":     "{others}不存在时{alias}是必须的",
+			"requiredWithoutAll":  "{others}都不存在时{alias}是必须的",
+			"present":             "{alias}字段必须存在",
+			"filled":              "{alias}不能为空",
+			"bool":                "{alias}只能是布尔型",
+			"alpha":               "{alias}只能是字母",
+			"alphaNumeric":        "{alias}只能是字母和数字",
+			"alphaDash":           "{alias}只能是字母数字和常规符号",
+			"between":             "{alias}必须在{min}到{max}之间",
+			"float":               "{alias}只能是小数",
+			"dateBefore":          "{alias}必须早于{time}",
+			"dateAfter":           "{alias}必须晚于{time}",
+			"equal":               "{alias}必须等于{other}",
+			"different":           "{alias}不能等于{other}",
+			"equalWithColumn":     "{alias}必须等于{other}",
+			"differentWithColumn": "{alias}不能等于{other}",
+			"in":                  "{alias}必须是{values}之一",
+			"integer":             "{alias}只能是整数",
+			"ip":                  "{alias}不是有效的IP地址",
+			"notIn":               "{alias}不能是{values}之一",
+			"length":              "{alias}长度必须为{length}",
+			"lengthMax":           "{alias}长度不能超过{max}",
+			"lengthMin":           "{alias}长度不能小于{min}",
+			"betweenLen":          "{alias}长度必须在{min}到{max}之间",
+			"minItems":            "{alias}不能少于{min}项",
+			"max":                 "{alias}不能大于{max}",
+			"min":                 "{alias}不能小于{min}",
+			"money":               "{alias}不是有效的货币金额",
+			"regexp":              "{alias}格式错误",
+			"notRegexp":           "{alias}格式错误",
+			"username":            "{alias}不是合法的用户名",
+			"host":                "{alias}不是有效的Host地址",
+			"email":               "{alias}不是有效的电子邮箱地址",
+			"creditCard":          "{alias}不是有效的银行卡号",
+			"numeric":             "{alias}只能是数值",
+			"hexColor":            "{alias}不是有效的Hex颜色",
+			"rgbColor":            "{alias}不是有效的RGB颜色",
+			"ascii":               "{alias}只能是ASCII字符",
+			"base64":              "{alias}不是有效的base64值",
+			"dnsName":             "{alias}不是有效的DNS名称",
+			"url":                 "{alias}不是有效的url地址",
+			"unique":              "{alias}已存在",
+			"exists":              "{alias}不存在",
+			"greaterThan":         "{alias}必须大于{min}",
+			"lessThan":            "{alias}必须小于{max}",
+			"format":              "{alias}不是有效的{format}",
+			"anyOf":               "{alias}不符合任一规则",
+			"oneOf":               "{alias}必须恰好符合一个规则",
+			"not":                 "{alias}不能符合该规则",
+			"type":                "{alias}类型错误",
+			"unknownField":        "{field}是未声明的字段",
+		},
+		LocaleEn: {
+			"required":            "{alias} is required",
+			"requiredIf":          "{alias} is required when {other} is {values}",
+			"requiredUnless":      "{alias} is required unless {other} is {values}",
+			"requiredWith":        "{alias} is required when {others} is present",
+			"requiredWithAll":     "{alias} is required when {others} are present",
+			"requiredWithout":     "{alias} is required when {others} is not present",
+			"requiredWithoutAll":  "{alias} is required when none of {others} are present",
+			"present":             "{alias} must be present",
+			"filled":              "{alias} must not be empty",
+			"bool":                "{alias} must be a boolean",
+			"alpha":               "{alias} may only contain letters",
+			"alphaNumeric":        "{alias} may only contain letters and numbers",
+			"alphaDash":           "{alias} may only contain letters, numbers, dashes and underscores",
+			"between":             "{alias} must be between {min} and {max}",
+			"float":               "{alias} must be a float",
+			"dateBefore":          "{alias} must be a time before {time}",
+			"dateAfter":           "{alias} must be a time after {time}",
+			"equal":               "{alias} must equal {other}",
+			"different":           "{alias} must be different from {other}",
+			"equalWithColumn":     "{alias} must equal {other}",
+			"differentWithColumn": "{alias} must be different from {other}",
+			"in":                  "{alias} must be one of {values}",
+			"integer":             "{alias} must be an integer",
+			"ip":                  "{alias} must be a valid IP address",
+			"notIn":               "{alias} must not be one of {values}",
+			"length":              "{alias} must be {length} characters",
+			"lengthMax":           "{alias} may not be greater than {max} characters",
+			"lengthMin":           "{alias} must be at least {min} characters",
+			"betweenLen":          "{alias} must be between {min} and {max} characters",
+			"minItems":            "{alias} must have at least {min} items",
+			"max":                 "{alias} may not be greater than {max}",
+			"min":                 "{alias} must be at least {min}",
+			"money":               "{alias} must be a valid amount of money",
+			"regexp":              "{alias} format is invalid",
+			"notRegexp":           "{alias} format is invalid",
+			"username":            "{alias} must be a valid username",
+			"host":                "{alias} must be a valid host",
+			"email":               "{alias} must be a valid email address",
+			"creditCard":          "{alias} must be a valid credit card number",
+			"numeric":             "{alias} must be numeric",
+			"hexColor":            "{alias} must be a valid hex color",
+			"rgbColor":            "{alias} must be a valid RGB color",
+			"ascii":               "{alias} may only contain ASCII characters",
+			"base64":              "{alias} must be a valid base64 string",
+			"dnsName":             "{alias} must be a valid DNS name",
+			"url":                 "{alias} must be a valid URL",
+			"unique":              "{alias} has already been taken",
+			"exists":              "{alias} does not exist",
+			"greaterThan":         "{alias} must be greater than {min}",
+			"lessThan":            "{alias} must be less than {max}",
+			"format":              "{alias} must be a valid {format}",
+			"anyOf":               "{alias} must match at least one schema",
+			"oneOf":               "{alias} must match exactly one schema",
+			"not":                 "{alias} must not match the schema",
+			"type":                "{alias} has an invalid type",
+			"unknownField":        "{field} is not allowed",
+		},
+	},
+}
+
+// SetDefaultLocale set the locale used when the message is missing in the schema locale
+func SetDefaultLocale(locale string) {
+	catalogs.Lock()
+	defer catalogs.Unlock()
+	catalogs.defaultLocale = locale
+}
+
+// SetMessages add or override the message templates of the locale, keyed by rule name
+func SetMessages(locale string, messages map[string]string) {
+
+	catalogs.Lock()
+	defer catalogs.Unlock()
+
+	catalog, ok := catalogs.messages[locale]
+	if !ok {
+		catalog = make(map[string]string)
+		catalogs.messages[locale] = catalog
+	}
+
+	for rule, message := range messages {
+		catalog[rule] = message
+	}
+}
+
+// LoadMessages add or override the message templates of the locale from JSON, e.g. {"required": "{alias} is required"}
+func LoadMessages(locale string, data []byte) error {
+
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return fmt.Errorf("govalidate: load %s messages: %v", locale, err)
+	}
+
+	SetMessages(locale, messages)
+
+	return nil
+}
+
+// LoadMessagesFile add or override the message templates of the locale from a JSON file
+func LoadMessagesFile(locale string, filename string) error {
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("govalidate: load %s messages: %v", locale, err)
+	}
+
+	return LoadMessages(locale, data)
+}
+
+// SetLocale set the locale of the default messages
+func (v *Validate) SetLocale(locale string) *Validate {
+	v.options.locale = locale
+	return v
+}
+
+// WithLocale get a copy of the schema using the locale of the default messages
+func (s *Schema) WithLocale(locale string) *Schema {
+	copied := *s
+	copied.options.locale = locale
+	return &copied
+}
+
+// lookupMessage find the message template of the rule: locale, the language of the locale, then the default locale
+func lookupMessage(locale string, rule string) string {
+
+	catalogs.RLock()
+	defer catalogs.RUnlock()
+
+	locales := []string{locale}
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locales = append(locales, locale[:i])
+	}
+	locales = append(locales, catalogs.defaultLocale)
+
+	for _, l := range locales {
+		if message, ok := catalogs.messages[l][rule]; ok {
+			return message
+		}
+	}
+
+	return ""
+}
+
+// newError create the error with the message rendered from the explicit message or the catalogs
+func (s *Schema) newError(field string, alias string, value interface{}, rule string, args []interface{}, message string) *Error {
+
+	e := &Error{
+		field:      field,
+		fieldAlias: alias,
+		fieldData:  value,
+		rule:       rule,
+		ruleArgs:   args,
+	}
+
+	if message == "" {
+		message = lookupMessage(s.options.locale, rule)
+	}
+	e.errorMessage = render(message, e)
+
+	return e
+}
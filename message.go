@@ -0,0 +1,95 @@
+package govalidate
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ruleArgNames the placeholder names of the built-in rule arguments, a name starting with "..." takes the remaining arguments
+var ruleArgNames = map[string][]string{
+	"requiredIf":          {"other", "...values"},
+	"requiredUnless":      {"other", "...values"},
+	"requiredWith":        {"...others"},
+	"requiredWithAll":     {"...others"},
+	"requiredWithout":     {"...others"},
+	"requiredWithoutAll":  {"...others"},
+	"between":             {"min", "max"},
+	"dateBefore":          {"time"},
+	"dateAfter":           {"time"},
+	"equal":               {"other"},
+	"different":           {"other"},
+	"equalWithColumn":     {"other"},
+	"differentWithColumn": {"other"},
+	"in":                  {"...values"},
+	"notIn":               {"...values"},
+	"length":              {"length"},
+	"lengthMax":           {"max"},
+	"lengthMin":           {"min"},
+	"betweenLen":          {"min", "max"},
+	"minItems":            {"min"},
+	"max":                 {"max"},
+	"min":                 {"min"},
+	"regexp":              {"pattern"},
+	"notRegexp":           {"pattern"},
+	"unique":              {"table", "column"},
+	"exists":              {"table", "column"},
+	"greaterThan":         {"min"},
+	"lessThan":            {"max"},
+	"format":              {"format"},
+}
+
+// render replace the placeholders of the message template: {field}, {alias}, {value}, {args}, {arg0}, {arg1}...
+// and the named arguments of the rule, e.g. "{alias}长度必须在{min}到{max}之间"
+func render(message string, e *Error) string {
+
+	if !strings.Contains(message, "{") {
+		return message
+	}
+
+	alias := e.fieldAlias
+	if alias == "" {
+		alias = e.field
+	}
+
+	args, _ := e.ruleArgs.([]interface{})
+
+	pairs := []string{
+		"{field}", e.field,
+		"{alias}", alias,
+		"{value}", display(e.fieldData),
+		"{args}", joinArgs(args),
+	}
+
+	for i, arg := range args {
+		pairs = append(pairs, "{arg"+strconv.Itoa(i)+"}", display(arg))
+	}
+
+	for i, name := range ruleArgNames[e.rule] {
+		if strings.HasPrefix(name, "...") {
+			if i < len(args) {
+				pairs = append(pairs, "{"+name[3:]+"}", joinArgs(args[i:]))
+			}
+			break
+		}
+		if i < len(args) {
+			pairs = append(pairs, "{"+name+"}", display(args[i]))
+		}
+	}
+
+	return strings.NewReplacer(pairs...).Replace(message)
+}
+
+func display(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return ToString(value)
+}
+
+func joinArgs(args []interface{}) string {
+	s := make([]string, 0, len(args))
+	for _, arg := range args {
+		s = append(s, display(arg))
+	}
+	return strings.Join(s, ",")
+}
@@ -0,0 +1,98 @@
+package govalidate
+
+import (
+	"context"
+	"sync"
+)
+
+// task a concrete path checked by the parallel executor
+type task struct {
+	column column
+	path   string
+	value  interface{}
+	errs   Errors
+	err    error
+	done   chan struct{}
+
+	// checked the errs are known before the check, e.g. a missing wildcard parent
+	checked bool
+}
+
+// SetConcurrency check the columns in parallel on at most n goroutines, n <= 1 checks them sequentially.
+// The rules of different columns must be safe for concurrent use, the errors keep the declaration order
+func (v *Validate) SetConcurrency(n int) *Validate {
+	v.options.concurrency = n
+	return v
+}
+
+// checkParallel check the paths on a bounded worker pool and merge the results in declaration order,
+// the in-flight checks are cancelled once the result is decided
+func (s *Schema) checkParallel(ctx context.Context, data map[string]interface{}, max int) *Result {
+
+	result := &Result{data: make(map[string]interface{})}
+
+	var tasks []*task
+	reported := make(map[string]bool)
+	for _, column := range s.columns {
+		paths, missing := expand(data, column.name)
+		for _, path := range paths {
+			tasks = append(tasks, &task{column: column, path: path, done: make(chan struct{})})
+		}
+		for _, parent := range missing {
+			if errs := s.checkParent(data, column, parent); len(errs) > 0 && !reported[parent] {
+				reported[parent] = true
+				tasks = append(tasks, &task{column: column, path: parent, errs: errs, checked: true, done: make(chan struct{})})
+			}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	queue := make(chan *task)
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.options.concurrency && i < len(tasks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				if t.err = ctx.Err(); t.err == nil && !t.checked {
+					t.value, t.errs, t.err = s.checkValue(ctx, data, t.column, t.path, max)
+				}
+				close(t.done)
+			}
+		}()
+	}
+
+	go func() {
+		defer close(queue)
+		for i, t := range tasks {
+			select {
+			case queue <- t:
+			case <-ctx.Done():
+				for _, t := range tasks[i:] {
+					t.err = ctx.Err()
+					close(t.done)
+				}
+				return
+			}
+		}
+	}()
+
+	// the paths before the first failure are waited for, so the errors are the same as the sequential ones
+	for _, t := range tasks {
+		<-t.done
+		if !result.merge(data, t.path, t.value, t.errs, t.err, max) {
+			cancel()
+			wg.Wait()
+			return result
+		}
+	}
+
+	wg.Wait()
+
+	s.checkUnknown(data, result, max)
+
+	return result
+}
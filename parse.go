@@ -0,0 +1,309 @@
+package govalidate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ruleSpec describe how a rule name in a rule string maps onto the Rule methods
+type ruleSpec struct {
+	args  int // number of arguments, -1 is one or more, -2 is any
+	build func(r *Rule, args []string, message string) error
+	refs  bool // the arguments may be $column references
+}
+
+// RefPrefix mark an argument of the bound rules in a rule string as a Ref, e.g. "max:$stock"
+const RefPrefix = "$"
+
+var ruleSpecs map[string]ruleSpec
+
+func init() {
+	ruleSpecs = map[string]ruleSpec{
+		"required":            noArg((*Rule).Required),
+		"requiredIf":          columnValuesArg((*Rule).RequiredIf),
+		"requiredUnless":      columnValuesArg((*Rule).RequiredUnless),
+		"requiredWith":        columnsArg((*Rule).RequiredWith),
+		"requiredWithAll":     columnsArg((*Rule).RequiredWithAll),
+		"requiredWithout":     columnsArg((*Rule).RequiredWithout),
+		"requiredWithoutAll":  columnsArg((*Rule).RequiredWithoutAll),
+		"present":             noArg((*Rule).Present),
+		"filled":              noArg((*Rule).Filled),
+		"nullable":            filterArg((*Rule).Nullable),
+		"trim":                filterArg((*Rule).Trim),
+		"lower":               filterArg((*Rule).Lower),
+		"upper":               filterArg((*Rule).Upper),
+		"stripTags":           filterArg((*Rule).StripTags),
+		"collapseSpaces":      filterArg((*Rule).CollapseSpaces),
+		"toInt":               filterArg((*Rule).ToInt),
+		"toFloat":             filterArg((*Rule).ToFloat),
+		"toBool":              filterArg((*Rule).ToBool),
+		"bool":                noArg((*Rule).Bool),
+		"alpha":               noArg((*Rule).Alpha),
+		"alphaNumeric":        noArg((*Rule).AlphaNumeric),
+		"alphaDash":           noArg((*Rule).AlphaDash),
+		"between":             intArgs((*Rule).Between),
+		"float":               noArg((*Rule).Float),
+		"dateBefore":          valueArg((*Rule).TimeBefore),
+		"dateAfter":           valueArg((*Rule).TimeAfter),
+		"timeBefore":          valueArg((*Rule).TimeBefore),
+		"timeAfter":           valueArg((*Rule).TimeAfter),
+		"equal":               valueArg((*Rule).Equal),
+		"different":           valueArg((*Rule).Different),
+		"equalWithColumn":     stringArg((*Rule).EqualWithColumn),
+		"differentWithColumn": stringArg((*Rule).DifferentWithColumn),
+		"in":                  listArg((*Rule).In),
+		"integer":             noArg((*Rule).Integer),
+		"ip":                  noArg((*Rule).IP),
+		"notIn":               listArg((*Rule).NotIn),
+		"length":              intArg((*Rule).Length),
+		"lengthMax":           intArg((*Rule).LengthMax),
+		"lengthMin":           intArg((*Rule).LengthMin),
+		"betweenLen":          intArgs((*Rule).BetweenLen),
+		"max":                 intArg((*Rule).Max),
+		"min":                 intArg((*Rule).Min),
+		"greaterThan":         intArg((*Rule).GreaterThan),
+		"lessThan":            intArg((*Rule).LessThan),
+		"money":               noArg((*Rule).Money),
+		"regexp":              patternArg((*Rule).Regexp),
+		"notRegexp":           patternArg((*Rule).NotRegexp),
+		"username":            noArg((*Rule).Username),
+		"host":                noArg((*Rule).Host),
+		"email":               noArg((*Rule).Email),
+		"creditCard":          noArg((*Rule).CreditCard),
+		"numeric":             noArg((*Rule).Numeric),
+		"hexColor":            noArg((*Rule).HexColor),
+		"rgbColor":            noArg((*Rule).RgbColor),
+		"ascii":               noArg((*Rule).ASCII),
+		"base64":              noArg((*Rule).Base64),
+		"dnsName":             noArg((*Rule).DNSName),
+		"url":                 noArg((*Rule).URL),
+	}
+}
+
+// ParseError rule string parse error
+type ParseError struct {
+	Column string
+	Rules  string // the rule string
+	Rule   string // the failed part of the rule string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("govalidate: column %s: parse rules %q: %q %s", e.Column, e.Rules, e.Rule, e.Reason)
+}
+
+// AddColumnRules add column with the rules of the rule string, e.g. "required|alphaNumeric|between:1,10|in:a,b,c",
+// the rule names are the built-in rule names or the names registered by RegisterRule,
+// the column is not added when the rule string has an error
+func (v *Validate) AddColumnRules(name string, alias string, rules string) (*Rule, error) {
+
+	parsed, err := v.parseRules(name, rules)
+	if err != nil {
+		return nil, err
+	}
+
+	r := v.AddColumn(name, alias)
+	r.item = append(r.item, parsed.item...)
+
+	return r, nil
+}
+
+// parseRules parse the rule string into a detached rule, which is added to the column only when parsing succeeds
+func (v *Validate) parseRules(column string, rules string) (*Rule, error) {
+
+	r := &Rule{}
+
+	for _, part := range strings.Split(rules, "|") {
+
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, arg := part, ""
+		if i := strings.Index(part, ":"); i >= 0 {
+			name, arg = part[:i], part[i+1:]
+		}
+
+		spec, ok := ruleSpecs[name]
+		if !ok {
+			if !v.registered(name) {
+				return nil, &ParseError{Column: column, Rules: rules, Rule: part, Reason: "is an unknown rule"}
+			}
+			spec = registeredSpec(name)
+		}
+
+		var args []string
+		switch {
+		case spec.args == 1:
+			// a single argument may contain commas, e.g. regexp:^[a-z]{1,3}$
+			args = []string{arg}
+		case arg != "":
+			args = strings.Split(arg, ",")
+		}
+
+		if (spec.args >= 0 && len(args) != spec.args) || (spec.args == -1 && len(args) == 0) || (spec.args == 1 && arg == "") {
+			return nil, &ParseError{Column: column, Rules: rules, Rule: part, Reason: "expects " + argCount(spec.args)}
+		}
+
+		if err := spec.build(r, args, ""); err != nil {
+			return nil, &ParseError{Column: column, Rules: rules, Rule: part, Reason: err.Error()}
+		}
+	}
+
+	return r, nil
+}
+
+func (v *Validate) registered(name string) bool {
+
+	if _, ok := v.rules[name]; ok {
+		return true
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	_, ok := registry.rules[name]
+	return ok
+}
+
+// registeredSpec use the registered rule, it takes any number of arguments
+func registeredSpec(name string) ruleSpec {
+	return ruleSpec{args: -2, build: func(r *Rule, args []string, message string) error {
+		list := make([]interface{}, 0, len(args))
+		for _, arg := range args {
+			list = append(list, arg)
+		}
+		r.Use(name, message, list...)
+		return nil
+	}}
+}
+
+func argCount(n int) string {
+	switch n {
+	case -1:
+		return "one or more arguments"
+	case 0:
+		return "no argument"
+	case 1:
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+func noArg(fn func(r *Rule, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 0, build: func(r *Rule, args []string, message string) error {
+		fn(r, message)
+		return nil
+	}}
+}
+
+// filterArg the rules without message, e.g. filters
+func filterArg(fn func(r *Rule) *Rule) ruleSpec {
+	return ruleSpec{args: 0, build: func(r *Rule, args []string, message string) error {
+		fn(r)
+		return nil
+	}}
+}
+
+func stringArg(fn func(r *Rule, arg string, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 1, build: func(r *Rule, args []string, message string) error {
+		fn(r, args[0], message)
+		return nil
+	}}
+}
+
+// patternArg compile the pattern so an invalid one is a parse error
+func patternArg(fn func(r *Rule, pattern interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 1, build: func(r *Rule, args []string, message string) error {
+		rxp, err := regexp.Compile(args[0])
+		if err != nil {
+			return err
+		}
+		fn(r, rxp, message)
+		return nil
+	}}
+}
+
+func valueArg(fn func(r *Rule, arg interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 1, build: func(r *Rule, args []string, message string) error {
+		fn(r, args[0], message)
+		return nil
+	}}
+}
+
+func listArg(fn func(r *Rule, list []interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: -1, build: func(r *Rule, args []string, message string) error {
+		list := make([]interface{}, 0, len(args))
+		for _, arg := range args {
+			list = append(list, strings.TrimSpace(arg))
+		}
+		fn(r, list, message)
+		return nil
+	}}
+}
+
+func columnsArg(fn func(r *Rule, columns []string, message string) *Rule) ruleSpec {
+	return ruleSpec{args: -1, build: func(r *Rule, args []string, message string) error {
+		columns := make([]string, 0, len(args))
+		for _, arg := range args {
+			columns = append(columns, strings.TrimSpace(arg))
+		}
+		fn(r, columns, message)
+		return nil
+	}}
+}
+
+func columnValuesArg(fn func(r *Rule, column string, values []interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: -1, build: func(r *Rule, args []string, message string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("expects a column and one or more values")
+		}
+		values := make([]interface{}, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			values = append(values, strings.TrimSpace(arg))
+		}
+		fn(r, strings.TrimSpace(args[0]), values, message)
+		return nil
+	}}
+}
+
+// intArg parse an integer or a $column reference, e.g. max:$stock
+func intArg(fn func(r *Rule, n interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 1, refs: true, build: func(r *Rule, args []string, message string) error {
+		n, err := intOrRef(args[0])
+		if err != nil {
+			return err
+		}
+		fn(r, n, message)
+		return nil
+	}}
+}
+
+func intArgs(fn func(r *Rule, a interface{}, b interface{}, message string) *Rule) ruleSpec {
+	return ruleSpec{args: 2, refs: true, build: func(r *Rule, args []string, message string) error {
+		var n [2]interface{}
+		for i, arg := range args {
+			var err error
+			if n[i], err = intOrRef(arg); err != nil {
+				return err
+			}
+		}
+		fn(r, n[0], n[1], message)
+		return nil
+	}}
+}
+
+func intOrRef(arg string) (interface{}, error) {
+	arg = strings.TrimSpace(arg)
+	if strings.HasPrefix(arg, RefPrefix) && len(arg) > len(RefPrefix) {
+		return Ref(arg[len(RefPrefix):]), nil
+	}
+	n, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("argument %q is not an integer", arg)
+	}
+	return n, nil
+}
@@ -0,0 +1,335 @@
+package govalidate
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Wildcard path segment matching every element of a slice, e.g. "items.*.sku"
+const Wildcard = "*"
+
+// Lookup get the value of the dot separated path, e.g. "address.city" or "items.3.sku", from nested maps, slices and structs.
+// A key of data equal to the whole path is matched first, so the flat keys containing dots, e.g. "user.name", are still found
+func Lookup(data map[string]interface{}, path string) (interface{}, bool) {
+
+	if value, ok := data[path]; ok {
+		return value, true
+	}
+
+	var value interface{} = data
+
+	for _, key := range strings.Split(path, ".") {
+		var ok bool
+		if value, ok = child(value, key); !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// normalizePath convert the bracket segments into dot segments, "items[*].sku" to "items.*.sku"
+func normalizePath(path string) string {
+	path = strings.Replace(path, "[", ".", -1)
+	path = strings.Replace(path, "]", "", -1)
+	return strings.TrimPrefix(path, ".")
+}
+
+// expand replace every wildcard segment of the path with the indexes of the slice in data,
+// the parents of the wildcards without any element, missing, empty or not a slice, are listed in missing
+func expand(data map[string]interface{}, path string) (paths []string, missing []string) {
+
+	if !strings.Contains(path, Wildcard) {
+		return []string{path}, nil
+	}
+
+	paths = []string{""}
+
+	for _, key := range strings.Split(path, ".") {
+
+		var next []string
+
+		for _, prefix := range paths {
+
+			if key != Wildcard {
+				next = append(next, join(prefix, key))
+				continue
+			}
+
+			node, ok := Lookup(data, prefix)
+			val := indirect(reflect.ValueOf(node))
+			if !ok || (val.Kind() != reflect.Slice && val.Kind() != reflect.Array) || val.Len() == 0 {
+				missing = append(missing, prefix)
+				continue
+			}
+
+			for i := 0; i < val.Len(); i++ {
+				next = append(next, join(prefix, strconv.Itoa(i)))
+			}
+		}
+
+		paths = next
+	}
+
+	return paths, missing
+}
+
+// relative replace the wildcard segments of other with the indexes of the concrete path,
+// so "items.*.type" refers to "items.3.type" when validating "items.3.sku"
+func relative(path string, other string) string {
+
+	other = normalizePath(other)
+	if !strings.Contains(other, Wildcard) {
+		return other
+	}
+
+	concrete := strings.Split(path, ".")
+	keys := strings.Split(other, ".")
+
+	for i, key := range keys {
+		if key == Wildcard && i < len(concrete) {
+			keys[i] = concrete[i]
+		}
+	}
+
+	return strings.Join(keys, ".")
+}
+
+func join(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+func indirect(val reflect.Value) reflect.Value {
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+func child(value interface{}, key string) (interface{}, bool) {
+
+	switch node := value.(type) {
+	case map[string]interface{}:
+		v, ok := node[key]
+		return v, ok
+	case M:
+		v, ok := node[key]
+		return v, ok
+	}
+
+	val := indirect(reflect.ValueOf(value))
+
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		v := val.MapIndex(reflect.ValueOf(key).Convert(val.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+		return v.Interface(), true
+	case reflect.Slice, reflect.Array:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= val.Len() {
+			return nil, false
+		}
+		return val.Index(i).Interface(), true
+	case reflect.Struct:
+		if f, ok := structField(val, key); ok {
+			return f.Interface(), true
+		}
+	}
+
+	return nil, false
+}
+
+// structField find the exported field by json tag name or field name, including the fields of embedded structs
+func structField(val reflect.Value, key string) (reflect.Value, bool) {
+
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+		if fieldKey(field) == key {
+			return val.Field(i), true
+		}
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !embedded(field) {
+			continue
+		}
+		if inner := indirect(val.Field(i)); inner.Kind() == reflect.Struct {
+			if f, ok := structField(inner, key); ok {
+				return f, true
+			}
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// embedded report whether the fields of the struct field are promoted like encoding/json does
+func embedded(field reflect.StructField) bool {
+
+	if !field.Anonymous || field.Tag.Get("json") != "" {
+		return false
+	}
+
+	typ := field.Type
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ.Kind() == reflect.Struct
+}
+
+// fieldKey get the key of the struct field, the json tag name if present
+func fieldKey(field reflect.StructField) string {
+
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// assign set the value of the path into out, rebuilding the nested maps and slices in the shape of data
+func assign(out map[string]interface{}, data map[string]interface{}, path string, value interface{}) {
+	if _, ok := data[path]; ok {
+		// the flat key keeps its shape like in Lookup
+		out[path] = value
+		return
+	}
+	keys := strings.Split(path, ".")
+	out[keys[0]] = put(out[keys[0]], data[keys[0]], keys[1:], value)
+}
+
+// put set the value into dst at keys, src is the input node at the same position,
+// the existing nodes are copied so the input data is never modified. The slice elements without any passed
+// path stay nil, so the indexes match the input and the error fields
+func put(dst interface{}, src interface{}, keys []string, value interface{}) interface{} {
+
+	if len(keys) == 0 {
+		return value
+	}
+
+	key := keys[0]
+	next, _ := child(src, key)
+
+	if kind := indirect(reflect.ValueOf(src)).Kind(); kind == reflect.Slice || kind == reflect.Array {
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 {
+			old, _ := dst.([]interface{})
+			size := len(old)
+			if i >= size {
+				size = i + 1
+			}
+			list := make([]interface{}, size)
+			copy(list, old)
+			list[i] = put(list[i], next, keys[1:], value)
+			return list
+		}
+	}
+
+	node := make(map[string]interface{})
+	switch old := dst.(type) {
+	case map[string]interface{}:
+		for k, v := range old {
+			node[k] = v
+		}
+	case M:
+		for k, v := range old {
+			node[k] = v
+		}
+	}
+	node[key] = put(node[key], next, keys[1:], value)
+
+	return node
+}
+
+// replace get a copy of data with the value set at path, only the nodes along the path are copied
+func replace(data map[string]interface{}, path string, value interface{}) map[string]interface{} {
+	if _, ok := data[path]; ok {
+		copied := make(map[string]interface{}, len(data))
+		for k, v := range data {
+			copied[k] = v
+		}
+		copied[path] = value
+		return copied
+	}
+	return replaceNode(data, strings.Split(path, "."), value).(map[string]interface{})
+}
+
+func replaceNode(node interface{}, keys []string, value interface{}) interface{} {
+
+	if len(keys) == 0 {
+		return value
+	}
+
+	key := keys[0]
+	val := indirect(reflect.ValueOf(node))
+
+	if kind := val.Kind(); kind == reflect.Slice || kind == reflect.Array {
+		if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < val.Len() {
+			list := make([]interface{}, val.Len())
+			for n := range list {
+				list[n] = val.Index(n).Interface()
+			}
+			list[i] = replaceNode(list[i], keys[1:], value)
+			return list
+		}
+	}
+
+	copied := make(map[string]interface{})
+
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() == reflect.String {
+			iter := val.MapRange()
+			for iter.Next() {
+				copied[iter.Key().String()] = iter.Value().Interface()
+			}
+		}
+	case reflect.Struct:
+		copied = structData(val)
+	}
+
+	copied[key] = replaceNode(copied[key], keys[1:], value)
+
+	return copied
+}
+
+// narrow get a copy of data where the slice of every wildcard of column holds only the element of the concrete path,
+// so the conditions of "items.*.price" see the element validated at "items.3.price"
+func narrow(data map[string]interface{}, column string, path string) map[string]interface{} {
+
+	keys := strings.Split(normalizePath(column), ".")
+	concrete := strings.Split(path, ".")
+
+	// the deepest wildcard first, the indexes of the shallower ones stay valid
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i] != Wildcard || i >= len(concrete) || concrete[i] == Wildcard {
+			continue
+		}
+		prefix := strings.Join(concrete[:i], ".")
+		if element, ok := Lookup(data, join(prefix, concrete[i])); ok {
+			data = replace(data, prefix, []interface{}{element})
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,55 @@
+package govalidate
+
+import (
+	"context"
+	"sync"
+)
+
+var registry = struct {
+	sync.RWMutex
+	rules map[string]ContextFunc
+}{rules: make(map[string]ContextFunc)}
+
+// RegisterRule register a global named rule, it can be used by every schema
+func RegisterRule(name string, fn Func) {
+	RegisterContextRule(name, withoutContext(fn))
+}
+
+// RegisterContextRule register a global named context aware rule, it can be used by every schema
+func RegisterContextRule(name string, fn ContextFunc) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.rules[name] = fn
+}
+
+// RegisterRule register a named rule only for this validate, it takes precedence over the global rule
+func (v *Validate) RegisterRule(name string, fn Func) *Validate {
+	return v.RegisterContextRule(name, withoutContext(fn))
+}
+
+// RegisterContextRule register a named context aware rule only for this validate, it takes precedence over the global rule
+func (v *Validate) RegisterContextRule(name string, fn ContextFunc) *Validate {
+	if v.rules == nil {
+		v.rules = make(map[string]ContextFunc)
+	}
+	v.rules[name] = fn
+	return v
+}
+
+func (s *Schema) lookupRule(name string) ContextFunc {
+
+	if fn, ok := s.rules[name]; ok {
+		return fn
+	}
+
+	registry.RLock()
+	defer registry.RUnlock()
+
+	return registry.rules[name]
+}
+
+func withoutContext(fn Func) ContextFunc {
+	return func(ctx context.Context, data map[string]interface{}, column string, args ...interface{}) (bool, error) {
+		return fn(data, column, args...), nil
+	}
+}
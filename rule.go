@@ -1,27 +1,211 @@
 package govalidate
 
+import (
+	"fmt"
+	"regexp"
+)
+
+// Ref rule argument referring to the value of another column, resolved at validation time,
+// e.g. Max(Ref("stock"), "") or TimeAfter(Ref("start_at"), "")
+type Ref string
+
 // Rule struct
 type Rule struct {
-	item []item
+	item         []item
+	as           coerceFunc
+	defaultFunc  func(data M) interface{}
+	defaultEmpty bool
 }
 
 type item struct {
-	name       string
-	message    string
-	args       []interface{}
-	verifyFunc Func
+	name        string
+	message     string
+	args        []interface{}
+	verifyFunc  Func
+	contextFunc ContextFunc
+	filter      FilterFunc
+	conditions  []Condition
+	implicit    bool  // run on missing and empty values
+	nullable    bool  // nil value stop the remaining rules
+	err         error // misconfiguration reported by Build
+}
+
+// Func validate func, the column may be a nested path, use Lookup(data, column) to get its value,
+// the Ref arguments are resolved to the values of the referenced columns before the call
+type Func func(data map[string]interface{}, column string, args ...interface{}) bool
+
+// Custom 自定义验证函数
+func (r *Rule) Custom(name string, fn Func, message string, args ...interface{}) *Rule {
+
+	r.item = append(r.item, item{
+		name:       name,
+		message:    message,
+		args:       args,
+		verifyFunc: fn,
+	})
+
+	return r
 }
 
-// Func validate func
-type Func func(data map[string]interface{}, column string, args ...interface{}) bool
+// Use 使用 RegisterRule 注册的命名规则
+func (r *Rule) Use(name string, message string, args ...interface{}) *Rule {
+
+	r.item = append(r.item, item{
+		name:    name,
+		message: message,
+		args:    args,
+	})
+
+	return r
+}
+
+// Default 字段缺失时使用默认值, 在所有规则之前执行
+func (r *Rule) Default(value interface{}) *Rule {
+	return r.DefaultFunc(func(data M) interface{} { return value })
+}
+
+// DefaultFunc 字段缺失时使用 fn 计算的默认值
+func (r *Rule) DefaultFunc(fn func(data M) interface{}) *Rule {
+	r.defaultFunc = fn
+	return r
+}
+
+// DefaultEmpty 字段为 nil 或空值时同样使用默认值
+func (r *Rule) DefaultEmpty() *Rule {
+	r.defaultEmpty = true
+	return r
+}
 
-// Required 必须存在值
+// Required 必须存在非空值, nil, 空字符串, 空数组和空 map 均视为空;
+// 通配字段如 items.*.sku 在 items 缺失或为空时, 以 items 报告 required 错误
 func (r *Rule) Required(message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "required",
 		message:    message,
 		verifyFunc: (&Validate{}).required,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredIf 当 column 的值为 values 之一时必须存在值
+func (r *Rule) RequiredIf(column string, values []interface{}, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredIf",
+		message:    message,
+		args:       append([]interface{}{column}, values...),
+		verifyFunc: (&Validate{}).requiredIf,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredUnless 除非 column 的值为 values 之一, 否则必须存在值
+func (r *Rule) RequiredUnless(column string, values []interface{}, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredUnless",
+		message:    message,
+		args:       append([]interface{}{column}, values...),
+		verifyFunc: (&Validate{}).requiredUnless,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredWith 当 columns 任一存在时必须存在值
+func (r *Rule) RequiredWith(columns []string, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredWith",
+		message:    message,
+		args:       stringsArgs(columns),
+		verifyFunc: (&Validate{}).requiredWith,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredWithAll 当 columns 全部存在时必须存在值
+func (r *Rule) RequiredWithAll(columns []string, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredWithAll",
+		message:    message,
+		args:       stringsArgs(columns),
+		verifyFunc: (&Validate{}).requiredWithAll,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredWithout 当 columns 任一不存在时必须存在值
+func (r *Rule) RequiredWithout(columns []string, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredWithout",
+		message:    message,
+		args:       stringsArgs(columns),
+		verifyFunc: (&Validate{}).requiredWithout,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// RequiredWithoutAll 当 columns 全部不存在时必须存在值
+func (r *Rule) RequiredWithoutAll(columns []string, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "requiredWithoutAll",
+		message:    message,
+		args:       stringsArgs(columns),
+		verifyFunc: (&Validate{}).requiredWithoutAll,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// Present 必须存在该字段, 值可以为空; 通配字段如 items.*.sku 在 items 缺失时, 以 items 报告 present 错误
+func (r *Rule) Present(message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "present",
+		message:    message,
+		verifyFunc: (&Validate{}).present,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// Filled 存在时值不能为空
+func (r *Rule) Filled(message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "filled",
+		message:    message,
+		verifyFunc: (&Validate{}).filled,
+		implicit:   true,
+	})
+
+	return r
+}
+
+// Nullable 值为 nil 时跳过之后的规则
+func (r *Rule) Nullable() *Rule {
+
+	r.item = append(r.item, item{
+		name:     "nullable",
+		nullable: true,
 	})
 
 	return r
@@ -75,8 +259,8 @@ func (r *Rule) AlphaDash(message string) *Rule {
 	return r
 }
 
-// Between 是否在 min max 之间
-func (r *Rule) Between(min int64, max int64, message string) *Rule {
+// Between 是否在 min max 之间, min max 为数值或 Ref
+func (r *Rule) Between(min interface{}, max interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "between",
@@ -158,7 +342,7 @@ func (r *Rule) EqualWithColumn(node string, message string) *Rule {
 	r.item = append(r.item, item{
 		name:       "equalWithColumn",
 		message:    message,
-		args:       []interface{}{node},
+		args:       []interface{}{Ref(node)},
 		verifyFunc: (&Validate{}).equalWithColumn,
 	})
 
@@ -171,7 +355,7 @@ func (r *Rule) DifferentWithColumn(node string, message string) *Rule {
 	r.item = append(r.item, item{
 		name:       "differentWithColumn",
 		message:    message,
-		args:       []interface{}{node},
+		args:       []interface{}{Ref(node)},
 		verifyFunc: (&Validate{}).differentWithColumn,
 	})
 
@@ -230,8 +414,8 @@ func (r *Rule) NotIn(s []interface{}, message string) *Rule {
 	return r
 }
 
-// Length 验证长度
-func (r *Rule) Length(len int64, message string) *Rule {
+// Length 验证长度, len 为整数或 Ref
+func (r *Rule) Length(len interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "length",
@@ -243,8 +427,8 @@ func (r *Rule) Length(len int64, message string) *Rule {
 	return r
 }
 
-// LengthMax 最大长度
-func (r *Rule) LengthMax(len int64, message string) *Rule {
+// LengthMax 最大长度, len 为整数或 Ref
+func (r *Rule) LengthMax(len interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "lengthMax",
@@ -256,8 +440,8 @@ func (r *Rule) LengthMax(len int64, message string) *Rule {
 	return r
 }
 
-// LengthMin 最小长度
-func (r *Rule) LengthMin(len int64, message string) *Rule {
+// LengthMin 最小长度, len 为整数或 Ref
+func (r *Rule) LengthMin(len interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "lengthMin",
@@ -269,8 +453,8 @@ func (r *Rule) LengthMin(len int64, message string) *Rule {
 	return r
 }
 
-// BetweenLen 长度范围
-func (r *Rule) BetweenLen(minLen int64, maxLen int64, message string) *Rule {
+// BetweenLen 长度范围, minLen maxLen 为整数或 Ref
+func (r *Rule) BetweenLen(minLen interface{}, maxLen interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "betweenLen",
@@ -282,8 +466,8 @@ func (r *Rule) BetweenLen(minLen int64, maxLen int64, message string) *Rule {
 	return r
 }
 
-// Max 最大值
-func (r *Rule) Max(max int64, message string) *Rule {
+// Max 最大值, max 为数值或 Ref
+func (r *Rule) Max(max interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "max",
@@ -295,19 +479,45 @@ func (r *Rule) Max(max int64, message string) *Rule {
 	return r
 }
 
-// Min 最小值
-func (r *Rule) Min(max int64, message string) *Rule {
+// Min 最小值, min 为数值或 Ref
+func (r *Rule) Min(min interface{}, message string) *Rule {
 
 	r.item = append(r.item, item{
 		name:       "min",
 		message:    message,
-		args:       []interface{}{max},
+		args:       []interface{}{min},
 		verifyFunc: (&Validate{}).min,
 	})
 
 	return r
 }
 
+// GreaterThan 大于 min, min 为数值或 Ref
+func (r *Rule) GreaterThan(min interface{}, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "greaterThan",
+		message:    message,
+		args:       []interface{}{min},
+		verifyFunc: (&Validate{}).greaterThan,
+	})
+
+	return r
+}
+
+// LessThan 小于 max, max 为数值或 Ref
+func (r *Rule) LessThan(max interface{}, message string) *Rule {
+
+	r.item = append(r.item, item{
+		name:       "lessThan",
+		message:    message,
+		args:       []interface{}{max},
+		verifyFunc: (&Validate{}).lessThan,
+	})
+
+	return r
+}
+
 // Money 有效货币金额
 func (r *Rule) Money(message string) *Rule {
 
@@ -321,19 +531,49 @@ func (r *Rule) Money(message string) *Rule {
 	return r
 }
 
-// Regexp 自定义正则
-func (r *Rule) Regexp(regexp string, message string) *Rule {
+// Regexp 自定义正则, pattern 为字符串或 *regexp.Regexp
+func (r *Rule) Regexp(pattern interface{}, message string) *Rule {
+	return r.pattern("regexp", pattern, message, (&Validate{}).regexp)
+}
+
+// NotRegexp 不匹配正则, pattern 为字符串或 *regexp.Regexp
+func (r *Rule) NotRegexp(pattern interface{}, message string) *Rule {
+	return r.pattern("notRegexp", pattern, message, (&Validate{}).notRegexp)
+}
+
+// pattern compile the pattern once, an invalid pattern is reported by Build
+func (r *Rule) pattern(name string, pattern interface{}, message string, fn Func) *Rule {
 
-	r.item = append(r.item, item{
-		name:       "regexp",
+	it := item{
+		name:       name,
 		message:    message,
-		args:       []interface{}{regexp},
-		verifyFunc: (&Validate{}).regexp,
-	})
+		args:       []interface{}{pattern},
+		verifyFunc: fn,
+	}
+
+	if rxp, err := compilePattern(pattern); err != nil {
+		it.err = err
+	} else {
+		it.args = []interface{}{rxp}
+	}
+
+	r.item = append(r.item, it)
 
 	return r
 }
 
+func compilePattern(pattern interface{}) (*regexp.Regexp, error) {
+	switch p := pattern.(type) {
+	case string:
+		return regexp.Compile(p)
+	case *regexp.Regexp:
+		if p != nil {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("pattern %v is not a string or *regexp.Regexp", pattern)
+}
+
 // Username 合法用户名
 func (r *Rule) Username(message string) *Rule {
 
@@ -476,3 +716,11 @@ func (r *Rule) URL(message string) *Rule {
 
 	return r
 }
+
+func stringsArgs(s []string) []interface{} {
+	args := make([]interface{}, 0, len(s))
+	for _, v := range s {
+		args = append(args, v)
+	}
+	return args
+}
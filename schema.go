@@ -0,0 +1,288 @@
+package govalidate
+
+import "context"
+
+// Schema is compiled columns, it is immutable and safe for concurrent use
+type Schema struct {
+	columns []column
+	options options
+	rules   map[string]ContextFunc
+}
+
+// Result is the result of a schema validate
+type Result struct {
+	data   M
+	errors Errors
+	err    error
+}
+
+// Schema compile the columns into a schema, later changes to v do not affect it. It panics with the BuildErrors
+// of the rules misconfigured when added, e.g. an invalid pattern, Build returns them as an error and checks the arguments too
+func (v *Validate) Schema() *Schema {
+	if errs := v.misconfigured(); len(errs) > 0 {
+		panic(errs)
+	}
+	return v.compile()
+}
+
+func (v *Validate) compile() *Schema {
+
+	columns := make([]column, 0, len(v.columns))
+
+	for _, c := range v.columns {
+		rule := *c.rule
+		rule.item = make([]item, 0, len(c.rule.item))
+		for _, it := range c.rule.item {
+			it.args = append([]interface{}(nil), it.args...)
+			it.conditions = append([]Condition(nil), it.conditions...)
+			rule.item = append(rule.item, it)
+		}
+		columns = append(columns, column{
+			name:  c.name,
+			alias: c.alias,
+			rule:  &rule,
+		})
+	}
+
+	rules := make(map[string]ContextFunc, len(v.rules))
+	for name, fn := range v.rules {
+		rules[name] = fn
+	}
+
+	options := v.options
+	options.allowKeys = append([]string(nil), v.options.allowKeys...)
+
+	return &Schema{columns: columns, options: options, rules: rules}
+}
+
+// Validate is map data validate, stop at the first failed rule
+func (s *Schema) Validate(data map[string]interface{}) *Result {
+	return s.checkContext(context.Background(), data, 1)
+}
+
+// ValidateAll validate every column and collect all failed rules
+func (s *Schema) ValidateAll(data map[string]interface{}) *Result {
+	return s.checkContext(context.Background(), data, s.options.maxErrors)
+}
+
+func (s *Schema) checkContext(ctx context.Context, data map[string]interface{}, max int) *Result {
+
+	if s.options.concurrency > 1 {
+		return s.checkParallel(ctx, data, max)
+	}
+
+	result := &Result{data: make(map[string]interface{})}
+
+	// the parents missing for several columns are reported once
+	reported := make(map[string]bool)
+
+	for _, column := range s.columns {
+
+		paths, missing := expand(data, column.name)
+
+		for _, path := range paths {
+
+			if result.err = ctx.Err(); result.err != nil {
+				return result
+			}
+
+			limit := 0
+			if max > 0 {
+				limit = max - len(result.errors)
+			}
+
+			value, errs, err := s.checkValue(ctx, data, column, path, limit)
+			if !result.merge(data, path, value, errs, err, max) {
+				return result
+			}
+		}
+
+		for _, parent := range missing {
+			errs := s.checkParent(data, column, parent)
+			if len(errs) == 0 || reported[parent] {
+				continue
+			}
+			reported[parent] = true
+			if !result.merge(data, parent, nil, errs, nil, max) {
+				return result
+			}
+		}
+	}
+
+	s.checkUnknown(data, result, max)
+
+	return result
+}
+
+// checkValue check the path and coerce the valid value to the column type
+func (s *Schema) checkValue(ctx context.Context, data map[string]interface{}, column column, path string, limit int) (interface{}, Errors, error) {
+
+	value, errs, err := s.checkPath(ctx, data, column, path, limit)
+	if err != nil {
+		return value, errs, err
+	}
+
+	if len(errs) == 0 && column.rule.as != nil && !isNil(value) {
+		if typed, err := column.rule.as(value); err != nil {
+			errs = Errors{s.newError(path, column.alias, value, "type", nil, "")}
+		} else {
+			value = typed
+		}
+	}
+
+	return value, errs, nil
+}
+
+// checkParent report the parent of a wildcard without any element when the column is Required or Present,
+// Required also rejects the empty slice, the other rules have no element to check
+func (s *Schema) checkParent(data map[string]interface{}, column column, parent string) Errors {
+
+	value, ok := Lookup(data, parent)
+	narrowed := narrow(data, column.name, parent)
+
+	for _, item := range column.rule.item {
+
+		// only the built-in rules, which are implicit, a Custom rule named present checks its own path
+		if !item.implicit || (item.name != "required" && item.name != "present") || !item.active(narrowed) {
+			continue
+		}
+
+		if !ok || (item.name == "required" && isEmpty(value)) {
+			return Errors{s.newError(parent, "", value, item.name, nil, "")}
+		}
+	}
+
+	return nil
+}
+
+// merge add the checked path to the result, report whether the validation goes on
+func (r *Result) merge(data map[string]interface{}, path string, value interface{}, errs Errors, err error, max int) bool {
+
+	if err != nil {
+		r.err = err
+		return false
+	}
+
+	if len(errs) == 0 {
+		assign(r.data, data, path, value)
+		return true
+	}
+
+	if max > 0 && len(r.errors)+len(errs) > max {
+		errs = errs[:max-len(r.errors)]
+	}
+	r.errors = append(r.errors, errs...)
+
+	return max <= 0 || len(r.errors) < max
+}
+
+// checkPath run the column rules and filters on the concrete path, stop when limit errors are collected,
+// return the filtered value
+func (s *Schema) checkPath(ctx context.Context, data map[string]interface{}, column column, path string, limit int) (interface{}, Errors, error) {
+
+	var errs Errors
+
+	value, ok := Lookup(data, path)
+
+	if rule := column.rule; rule.defaultFunc != nil && (!ok || (rule.defaultEmpty && isEmpty(value))) {
+		value, ok = rule.defaultFunc(data), true
+		data = replace(data, path, value)
+	}
+
+	for _, item := range column.rule.item {
+
+		if len(item.conditions) > 0 && !item.active(narrow(data, column.name, path)) {
+			continue
+		}
+
+		if item.filter != nil {
+			if ok {
+				// the later rules see the filtered value, the input data is never modified
+				value = item.filter(value)
+				data = replace(data, path, value)
+			}
+			continue
+		}
+
+		if item.nullable {
+			if ok && isNil(value) {
+				break
+			}
+			continue
+		}
+
+		if !item.implicit && s.options.emptyMode == EmptySkip && ok && isEmpty(value) {
+			continue
+		}
+
+		args := resolveArgs(data, path, item.args)
+
+		valid, err := s.verify(ctx, item, data, path, args)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return value, errs, ctxErr
+			}
+			return value, errs, &LookupError{Field: path, Rule: item.name, Err: err}
+		}
+
+		if !valid {
+			// the placeholders show the names of the Ref arguments, not the values of the other columns
+			e := s.newError(path, column.alias, value, item.name, item.args, item.message)
+			e.ruleArgs = args
+			errs = append(errs, e)
+
+			if limit > 0 && len(errs) >= limit {
+				break
+			}
+		}
+	}
+
+	return value, errs, nil
+}
+
+// resolveArgs replace the Ref arguments with the values of the referenced columns,
+// the wildcards of the Ref are replaced with the indexes of the concrete path
+func resolveArgs(data map[string]interface{}, path string, args []interface{}) []interface{} {
+
+	var resolved []interface{}
+
+	for i, arg := range args {
+		ref, ok := arg.(Ref)
+		if !ok {
+			continue
+		}
+		if resolved == nil {
+			resolved = append([]interface{}(nil), args...)
+		}
+		resolved[i], _ = Lookup(data, relative(path, string(ref)))
+	}
+
+	if resolved == nil {
+		return args
+	}
+
+	return resolved
+}
+
+// Valid report whether the data passed, false if the validation stopped on Err
+func (r *Result) Valid() bool {
+	return len(r.errors) == 0 && r.err == nil
+}
+
+// FirstError get the first error
+func (r *Result) FirstError() *Error {
+	if len(r.errors) == 0 {
+		return nil
+	}
+	return r.errors[0]
+}
+
+// Errors get all collected errors
+func (r *Result) Errors() Errors {
+	return r.errors
+}
+
+// GetData get validate data
+func (r *Result) GetData() map[string]interface{} {
+	return r.data
+}
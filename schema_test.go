@@ -0,0 +1,126 @@
+package govalidate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchemaConcurrent(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "").Required("").AlphaNumeric("").LengthMin(4, "")
+	v.AddColumn("age", "").Integer("").Between(1, 150, "")
+
+	schema := v.Schema()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			valid := i%2 == 0
+			data := M{"username": fmt.Sprintf("user%d", i), "age": i + 1}
+			if !valid {
+				data["username"] = "bad name"
+			}
+
+			for n := 0; n < 50; n++ {
+				result := schema.ValidateAll(data)
+				if result.Valid() != valid {
+					t.Errorf("Expected %v to be %v, got %v", data, valid, result.Valid())
+					return
+				}
+				if valid && result.GetData()["username"] != data["username"] {
+					t.Errorf("Unexpected data %v", result.GetData())
+					return
+				}
+				if !valid && result.FirstError().GetField() != "username" {
+					t.Errorf("Unexpected error %v", result.FirstError())
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSchemaImmutable(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "").Required("")
+
+	schema := v.Schema()
+
+	v.AddColumn("username", "").Length(4, "")
+	v.AddColumn("status", "").Required("")
+
+	if result := schema.Validate(M{"username": "tester"}); !result.Valid() {
+		t.Errorf("Expected schema to ignore later rules, got %v", result.FirstError())
+	}
+
+	if v.Validate(M{"username": "tester"}) != false {
+		t.Error("Expected Validate to use the later rules")
+	}
+}
+
+func TestResultTyped(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("age", "").Integer("").As(TypeInt64)
+	v.AddColumn("score", "").Float("").As(TypeFloat64)
+	v.AddColumn("status", "").Bool("").AsBool()
+	v.AddColumn("birthday", "").AsTime("2006-01-02")
+	v.AddColumn("password", "").As(TypeString)
+	v.AddColumn("count", "").As(TypeInt64)
+
+	schema := v.Schema()
+
+	result := schema.Validate(M{
+		"age":      "18",
+		"score":    "9.5",
+		"status":   "true",
+		"birthday": "2000-01-02",
+		"password": 12345,
+		"count":    float64(3),
+	})
+	if !result.Valid() {
+		t.Fatal(result.FirstError())
+	}
+
+	data := result.GetData()
+	if data["age"] != int64(18) || data["score"] != 9.5 || data["status"] != true || data["password"] != "12345" || data["count"] != int64(3) {
+		t.Errorf("Unexpected typed data %#v", data)
+	}
+
+	if result.Int64("age") != 18 || result.Float64("score") != 9.5 || !result.Bool("status") || result.String("password") != "12345" {
+		t.Errorf("Unexpected typed getters %#v", data)
+	}
+
+	if birthday := result.Time("birthday"); birthday != time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC) {
+		t.Errorf("Unexpected birthday %v", birthday)
+	}
+
+	if result.Int64("missing") != 0 || result.String("missing") != "" || !result.Time("missing").IsZero() {
+		t.Error("Expected zero values for missing columns")
+	}
+
+	result = schema.Validate(M{"birthday": "02/01/2000"})
+	if result.Valid() || result.FirstError().GetRule() != "type" || !errors.Is(result.FirstError(), ErrType) {
+		t.Errorf("Expected type error, got %v", result.FirstError())
+	}
+
+	result = schema.Validate(M{"count": 1.5})
+	if result.Valid() || result.FirstError().GetField() != "count" {
+		t.Errorf("Expected type error for count, got %v", result.FirstError())
+	}
+}
@@ -0,0 +1,167 @@
+package govalidate
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+var (
+	structSchemas sync.Map
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+// FromStruct build the columns from the struct tags, e.g. `validate:"required|alphaNumeric|length:4" label:"登录账户"`,
+// the json tag names are used as the column names, nested structs and slices of structs are added as nested columns
+func FromStruct(ptr interface{}) (*Validate, error) {
+
+	typ := reflect.TypeOf(ptr)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("govalidate: %T is not a struct", ptr)
+	}
+
+	v := New()
+	if err := v.addStruct(typ, "", map[reflect.Type]bool{}); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// ValidateStruct validate the struct by its tags, stop at the first failed rule
+func ValidateStruct(ptr interface{}) (*Result, error) {
+
+	schema, data, err := structSchema(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.Validate(data), nil
+}
+
+// ValidateStructAll validate every field of the struct by its tags and collect all failed rules
+func ValidateStructAll(ptr interface{}) (*Result, error) {
+
+	schema, data, err := structSchema(ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.ValidateAll(data), nil
+}
+
+// structSchema get the cached schema of the struct type and the struct as data
+func structSchema(ptr interface{}) (*Schema, M, error) {
+
+	val := indirect(reflect.ValueOf(ptr))
+	if val.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("govalidate: %T is not a struct", ptr)
+	}
+
+	schema, ok := structSchemas.Load(val.Type())
+	if !ok {
+		v, err := FromStruct(ptr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if errs := v.misconfigured(); len(errs) > 0 {
+			return nil, nil, errs
+		}
+		schema, _ = structSchemas.LoadOrStore(val.Type(), v.Schema())
+	}
+
+	return schema.(*Schema), structData(val), nil
+}
+
+func (v *Validate) addStruct(typ reflect.Type, prefix string, visiting map[reflect.Type]bool) error {
+
+	if visiting[typ] {
+		return nil
+	}
+	visiting[typ] = true
+	defer delete(visiting, typ)
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if embedded(field) {
+			if err := v.addStruct(ft, prefix, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		path := join(prefix, fieldKey(field))
+
+		rules, hasRules := field.Tag.Lookup("validate")
+		label, hasLabel := field.Tag.Lookup("label")
+		if hasRules || hasLabel {
+			parsed, err := v.parseRules(path, rules)
+			if err != nil {
+				return err
+			}
+			r := v.AddColumn(path, label)
+			r.item = append(r.item, parsed.item...)
+		}
+
+		if ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array {
+			path = join(path, Wildcard)
+			for ft = ft.Elem(); ft.Kind() == reflect.Ptr; ft = ft.Elem() {
+			}
+		}
+
+		if ft.Kind() == reflect.Struct && ft != timeType {
+			if err := v.addStruct(ft, path, visiting); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// structData get the top level fields of the struct as data, nested values are resolved by Lookup
+func structData(val reflect.Value) M {
+
+	data := make(M)
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+
+		field := typ.Field(i)
+
+		if embedded(field) {
+			if inner := indirect(val.Field(i)); inner.Kind() == reflect.Struct {
+				for k, v := range structData(inner) {
+					if _, ok := data[k]; !ok {
+						data[k] = v
+					}
+				}
+			}
+			continue
+		}
+
+		if field.PkgPath != "" || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		data[fieldKey(field)] = val.Field(i).Interface()
+	}
+
+	return data
+}
@@ -0,0 +1,130 @@
+package govalidate
+
+import (
+	"strings"
+	"testing"
+)
+
+type structAddress struct {
+	City string `json:"city" validate:"required|alpha" label:"城市"`
+}
+
+type structItem struct {
+	Sku string `json:"sku" validate:"required|alphaNumeric"`
+}
+
+type structBase struct {
+	Status string `json:"status" validate:"in:on,off"`
+}
+
+type structRequest struct {
+	structBase
+	Username string         `json:"username" validate:"required|alphaNumeric|length:4" label:"登录账户"`
+	Age      int            `json:"age" validate:"between:1,150"`
+	Address  *structAddress `json:"address"`
+	Items    []structItem   `json:"items"`
+	Tags     []string       `json:"tags" validate:"lengthMax:3"`
+	Ignored  string         `json:"-" validate:"required"`
+	internal string
+}
+
+func TestValidateStruct(t *testing.T) {
+
+	t.Parallel()
+
+	valid := func() *structRequest {
+		return &structRequest{
+			structBase: structBase{Status: "on"},
+			Username:   "test",
+			Age:        18,
+			Address:    &structAddress{City: "Beijing"},
+			Items:      []structItem{{"A1"}, {"B2"}},
+			Tags:       []string{"a"},
+		}
+	}
+
+	var tests = []*struct {
+		modify   func(r *structRequest)
+		expected bool
+		field    string
+		alias    string
+	}{
+		{func(r *structRequest) {}, true, "", ""},
+		{func(r *structRequest) { r.Username = "tester" }, false, "username", "登录账户"},
+		{func(r *structRequest) { r.Age = 200 }, false, "age", ""},
+		{func(r *structRequest) { r.Address.City = "Bei jing" }, false, "address.city", "城市"},
+		{func(r *structRequest) { r.Address = nil }, false, "address.city", "城市"},
+		{func(r *structRequest) { r.Items[1].Sku = "B-2" }, false, "items.1.sku", ""},
+		{func(r *structRequest) { r.Status = "none" }, false, "status", ""},
+		{func(r *structRequest) { r.Tags = []string{"a", "b", "c", "d"} }, false, "tags", ""},
+	}
+
+	for _, test := range tests {
+		req := valid()
+		test.modify(req)
+
+		result, err := ValidateStruct(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Valid() != test.expected {
+			t.Error(req, test.expected, result.Valid(), result.FirstError())
+			continue
+		}
+		if !test.expected {
+			if e := result.FirstError(); e.GetField() != test.field || e.GetFieldAlias() != test.alias {
+				t.Errorf("Expected field %q %q, got %q %q", test.field, test.alias, e.GetField(), e.GetFieldAlias())
+			}
+		}
+	}
+
+	result, _ := ValidateStruct(valid())
+	if sku, _ := Lookup(result.GetData(), "items.1.sku"); sku != "B2" {
+		t.Errorf("Expected items.1.sku to be B2, got %v", result.GetData())
+	}
+
+	req := valid()
+	req.Username, req.Age, req.Items[1].Sku = "tester", 200, "B-2"
+
+	result, err := ValidateStructAll(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields := result.Errors().Fields(); strings.Join(fields, ",") != "username,age,items.1.sku" {
+		t.Errorf("Expected every failed field, got %v", fields)
+	}
+
+	if _, err := ValidateStructAll("test"); err == nil {
+		t.Error("Expected error for non struct")
+	}
+}
+
+func TestValidateStructInvalid(t *testing.T) {
+
+	t.Parallel()
+
+	if _, err := ValidateStruct("test"); err == nil {
+		t.Error("Expected error for non struct")
+	}
+
+	var tests = []interface{}{
+		&struct {
+			Name string `validate:"required|unknown"`
+		}{},
+		&struct {
+			Name string `validate:"length"`
+		}{},
+		&struct {
+			Name string `validate:"between:1,a"`
+		}{},
+		&struct {
+			Name string `validate:"required:1"`
+		}{},
+	}
+
+	for _, test := range tests {
+		if _, err := ValidateStruct(test); err == nil {
+			t.Errorf("Expected error for %#v", test)
+		}
+	}
+}
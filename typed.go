@@ -0,0 +1,132 @@
+package govalidate
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Type output type of a column
+type Type int
+
+// Output types
+const (
+	TypeString Type = iota + 1
+	TypeInt64
+	TypeFloat64
+	TypeBool
+	TypeTime
+)
+
+// coerceFunc convert the validated value into the output type
+type coerceFunc func(value interface{}) (interface{}, error)
+
+// As 验证通过后将值转换为 t 类型, 转换失败时报告 type 错误
+func (r *Rule) As(t Type) *Rule {
+
+	switch t {
+	case TypeString:
+		r.as = func(value interface{}) (interface{}, error) { return ToString(value), nil }
+	case TypeInt64:
+		r.as = func(value interface{}) (interface{}, error) { return toInt64(value) }
+	case TypeFloat64:
+		r.as = func(value interface{}) (interface{}, error) { return ToFloat(value) }
+	case TypeBool:
+		r.as = func(value interface{}) (interface{}, error) { return ToBoolean(ToString(value)) }
+	case TypeTime:
+		r.as = func(value interface{}) (interface{}, error) { return ToTime(value) }
+	}
+
+	return r
+}
+
+// AsTime 验证通过后按 layout 将字符串转换为 time.Time
+func (r *Rule) AsTime(layout string) *Rule {
+
+	r.as = func(value interface{}) (interface{}, error) {
+		if s, ok := value.(string); ok {
+			return time.Parse(layout, s)
+		}
+		return ToTime(value)
+	}
+
+	return r
+}
+
+// AsBool 验证通过后转换为 bool
+func (r *Rule) AsBool() *Rule {
+	return r.As(TypeBool)
+}
+
+// toInt64 convert integers, integral floats and integer strings to int64
+func toInt64(value interface{}) (interface{}, error) {
+
+	if res, err := ToInt(value); err == nil {
+		return res, nil
+	}
+
+	f, err := ToFloat(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if f != math.Trunc(f) {
+		return nil, fmt.Errorf("conversion failed, %v is not an integer", value)
+	}
+
+	return int64(f), nil
+}
+
+// Get get the validated value of the path
+func (r *Result) Get(path string) (interface{}, bool) {
+	return Lookup(r.data, normalizePath(path))
+}
+
+// String get the validated value of the path as string, empty if missing
+func (r *Result) String(path string) string {
+	value, ok := r.Get(path)
+	if !ok || value == nil {
+		return ""
+	}
+	return ToString(value)
+}
+
+// Int64 get the validated value of the path as int64, 0 if missing or not an integer
+func (r *Result) Int64(path string) int64 {
+	value, _ := r.Get(path)
+	res, err := toInt64(value)
+	if err != nil {
+		return 0
+	}
+	return res.(int64)
+}
+
+// Float64 get the validated value of the path as float64, 0 if missing or not a number
+func (r *Result) Float64(path string) float64 {
+	value, _ := r.Get(path)
+	res, _ := ToFloat(value)
+	return res
+}
+
+// Bool get the validated value of the path as bool, false if missing or not a boolean
+func (r *Result) Bool(path string) bool {
+	value, ok := r.Get(path)
+	if !ok {
+		return false
+	}
+	res, _ := ToBoolean(ToString(value))
+	return res
+}
+
+// Time get the validated value of the path as time.Time, zero if missing or not a time
+func (r *Result) Time(path string) time.Time {
+	value, ok := r.Get(path)
+	if !ok || value == nil {
+		return time.Time{}
+	}
+	res, err := ToTime(value)
+	if err != nil {
+		return time.Time{}
+	}
+	return res
+}
@@ -0,0 +1,176 @@
+package govalidate
+
+import (
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnknownMode how the keys not added by AddColumn are treated
+type UnknownMode int
+
+const (
+	// UnknownStrip unknown keys are dropped from the validated data, the default
+	UnknownStrip UnknownMode = iota
+	// UnknownReject unknown keys are reported as unknownField errors
+	UnknownReject
+)
+
+// SetUnknownMode set how the keys not added by AddColumn are treated
+func (v *Validate) SetUnknownMode(mode UnknownMode) *Validate {
+	v.options.unknownMode = mode
+	return v
+}
+
+// AllowKeys allow the keys matching the patterns to pass through untouched, the pattern segments are separated by dots
+// and matched by path.Match, e.g. "meta", "meta.*" or "x_*"
+func (v *Validate) AllowKeys(patterns ...string) *Validate {
+	for _, pattern := range patterns {
+		v.options.allowKeys = append(v.options.allowKeys, normalizePath(pattern))
+	}
+	return v
+}
+
+// checkUnknown report or pass through the keys which are not columns
+func (s *Schema) checkUnknown(data map[string]interface{}, result *Result, max int) {
+
+	if s.options.unknownMode == UnknownStrip && len(s.options.allowKeys) == 0 {
+		return
+	}
+
+	for _, key := range s.unknownKeys(data, "") {
+
+		value, _ := Lookup(data, key)
+
+		if s.allowed(key) {
+			assign(result.data, data, key, value)
+			continue
+		}
+
+		if s.options.unknownMode != UnknownReject {
+			continue
+		}
+
+		result.errors = append(result.errors, s.newError(key, "", value, "unknownField", nil, ""))
+
+		if max > 0 && len(result.errors) >= max {
+			return
+		}
+	}
+}
+
+// unknownKeys get the keys under prefix which are neither columns nor the parents of columns
+func (s *Schema) unknownKeys(node interface{}, prefix string) []string {
+
+	var unknown []string
+
+	for _, key := range childKeys(node) {
+
+		current := join(prefix, key)
+
+		switch s.known(current) {
+		case knownColumn:
+		case knownParent:
+			value, _ := child(node, key)
+			unknown = append(unknown, s.unknownKeys(value, current)...)
+		default:
+			if value, _ := child(node, key); !s.allowed(current) && s.allowedBelow(current) && len(childKeys(value)) > 0 {
+				// the allow patterns such as "meta.*" match the keys inside the unknown node
+				unknown = append(unknown, s.unknownKeys(value, current)...)
+				continue
+			}
+			unknown = append(unknown, current)
+		}
+	}
+
+	return unknown
+}
+
+const (
+	knownNone = iota
+	knownColumn
+	knownParent
+)
+
+// known report whether the concrete path is the parent of a column, a column or unknown
+func (s *Schema) known(concrete string) int {
+
+	keys := strings.Split(concrete, ".")
+	res := knownNone
+
+	for _, column := range s.columns {
+		pattern := strings.Split(column.name, ".")
+		if len(pattern) < len(keys) || !matchKeys(pattern[:len(keys)], keys) {
+			continue
+		}
+		if len(pattern) > len(keys) {
+			// the declared children define the keys of the object, even if it is a column itself
+			return knownParent
+		}
+		res = knownColumn
+	}
+
+	return res
+}
+
+func (s *Schema) allowed(concrete string) bool {
+
+	keys := strings.Split(concrete, ".")
+
+	for _, pattern := range s.options.allowKeys {
+		if p := strings.Split(pattern, "."); len(p) == len(keys) && matchKeys(p, keys) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowedBelow report whether an allow pattern matches keys under the concrete path
+func (s *Schema) allowedBelow(concrete string) bool {
+
+	keys := strings.Split(concrete, ".")
+
+	for _, pattern := range s.options.allowKeys {
+		if p := strings.Split(pattern, "."); len(p) > len(keys) && matchKeys(p[:len(keys)], keys) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchKeys(pattern []string, keys []string) bool {
+	for i, key := range keys {
+		if ok, _ := path.Match(pattern[i], key); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// childKeys get the sorted keys of maps and the indexes of slices, structs and other values have no child keys
+func childKeys(node interface{}) []string {
+
+	var keys []string
+	val := indirect(reflect.ValueOf(node))
+
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, k := range val.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			keys = append(keys, strconv.Itoa(i))
+		}
+	}
+
+	return keys
+}
@@ -1,6 +1,8 @@
 package govalidate
 
 import (
+	"context"
+	"reflect"
 	"regexp"
 )
 
@@ -10,14 +12,48 @@ type M map[string]interface{}
 // Validate struct
 type Validate struct {
 	columns []column
+	options options
+	rules   map[string]ContextFunc
 	data    M
 	error   *Error
+	errors  Errors
+	err     error
 }
 
+type options struct {
+	maxErrors   int
+	emptyMode   EmptyMode
+	unknownMode UnknownMode
+	allowKeys   []string
+	locale      string
+	concurrency int
+}
+
+// EmptyMode how the rules treat nil and empty values
+type EmptyMode int
+
+const (
+	// EmptyValidate nil and empty values are validated by every rule, the default
+	EmptyValidate EmptyMode = iota
+	// EmptySkip nil and empty values are only validated by the required, present and filled rules, the other rules pass as if missing
+	EmptySkip
+)
+
 type column struct {
-	name  string
-	alias string
-	rule  *Rule
+	name    string
+	alias   string
+	rule    *Rule
+	aliases []string // conflicting aliases of the later declarations, reported by Build
+}
+
+// conflicts report whether the conflicting alias is already recorded
+func (c *column) conflicts(alias string) bool {
+	for _, a := range c.aliases {
+		if a == alias {
+			return true
+		}
+	}
+	return false
 }
 
 // New start run
@@ -28,6 +64,8 @@ func New() *Validate {
 // AddColumn add column
 func (v *Validate) AddColumn(name string, alias string) *Rule {
 
+	name = normalizePath(name)
+
 	if !v.insetColumn(name) {
 		v.columns = append(v.columns, column{
 			name:  name,
@@ -36,41 +74,85 @@ func (v *Validate) AddColumn(name string, alias string) *Rule {
 		})
 	}
 
+	for i := range v.columns {
+		if c := &v.columns[i]; c.name == name && alias != "" && c.alias != "" && alias != c.alias && !c.conflicts(alias) {
+			c.aliases = append(c.aliases, alias)
+		}
+	}
+
 	return v.getColumn(name).rule
 }
 
-// Validate is map data validate
+// Validate is map data validate, stop at the first failed rule
 func (v *Validate) Validate(data map[string]interface{}) bool {
+	return v.run(data, 1)
+}
 
-	v.data = make(map[string]interface{})
+// ValidateAll validate every column and collect all failed rules
+func (v *Validate) ValidateAll(data map[string]interface{}) bool {
+	return v.run(data, v.options.maxErrors)
+}
 
-	for _, column := range v.columns {
+// SetMaxErrors set the maximum number of errors collected by ValidateAll, 0 is unlimited
+func (v *Validate) SetMaxErrors(max int) *Validate {
+	v.options.maxErrors = max
+	return v
+}
 
-		rules := column.rule
+// SetEmptyMode set how the rules treat nil and empty values
+func (v *Validate) SetEmptyMode(mode EmptyMode) *Validate {
+	v.options.emptyMode = mode
+	return v
+}
 
-		for _, item := range rules.item {
+func (v *Validate) run(data map[string]interface{}, max int) bool {
+	valid, _ := v.runContext(context.Background(), data, max)
+	return valid
+}
 
-			if !item.verifyFunc(data, column.name, item.args...) {
-				v.error = new(Error)
-				v.error.field = column.name
-				v.error.fieldAlias = column.alias
-				v.error.fieldData = data[column.name]
-				v.error.rule = item.name
-				v.error.ruleArgs = item.args
-				v.error.errorMessage = item.message
-				return false
-			}
-		}
+func (v *Validate) runContext(ctx context.Context, data map[string]interface{}, max int) (bool, error) {
 
-		v.data[column.name] = data[column.name]
+	result := &Result{}
+	if errs := v.misconfigured(); len(errs) > 0 {
+		// no rule runs, the misconfigured rules stop the validation like a lookup error
+		result.err = errs
+	} else {
+		result = (&Schema{columns: v.columns, options: v.options, rules: v.rules}).checkContext(ctx, data, max)
 	}
-	return true
+
+	v.data = result.data
+	v.errors = result.errors
+	v.err = result.Err()
+
+	if v.err != nil {
+		// Error and Errors are set whenever the validation fails, also on a lookup error
+		v.errors = append(v.errors, lookupFailure(v.err))
+	}
+
+	v.error = nil
+	if len(v.errors) > 0 {
+		v.error = v.errors[0]
+	}
+
+	return result.Valid(), v.err
 }
 
+// Error get the first error
 func (v *Validate) Error() *Error {
 	return v.error
 }
 
+// Err get the context error, the *LookupError or the BuildErrors of the misconfigured rules which stopped the last validation,
+// nil if the rules decided
+func (v *Validate) Err() error {
+	return v.err
+}
+
+// Errors get all collected errors
+func (v *Validate) Errors() Errors {
+	return v.errors
+}
+
 // GetData get validate data
 func (v *Validate) GetData() map[string]interface{} {
 	return v.data
@@ -95,13 +177,116 @@ func (v *Validate) getColumn(name string) column {
 }
 
 func (v *Validate) required(data map[string]interface{}, column string, args ...interface{}) bool {
-	_, ok := data[column]
+	value, ok := Lookup(data, column)
+	return ok && !isEmpty(value)
+}
+
+func (v *Validate) present(data map[string]interface{}, column string, args ...interface{}) bool {
+	_, ok := Lookup(data, column)
 	return ok
 }
 
+func (v *Validate) filled(data map[string]interface{}, column string, args ...interface{}) bool {
+	value, ok := Lookup(data, column)
+	return !ok || !isEmpty(value)
+}
+
+func (v *Validate) requiredIf(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if len(args) < 2 {
+		return false
+	}
+
+	if !matchAny(data, relative(column, ToString(args[0])), args[1:]) {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+func (v *Validate) requiredUnless(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if len(args) < 2 {
+		return false
+	}
+
+	if matchAny(data, relative(column, ToString(args[0])), args[1:]) {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+func (v *Validate) requiredWith(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if v.countPresent(data, column, args) == 0 {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+func (v *Validate) requiredWithAll(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if v.countPresent(data, column, args) < len(args) {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+func (v *Validate) requiredWithout(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if v.countPresent(data, column, args) == len(args) {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+func (v *Validate) requiredWithoutAll(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	if v.countPresent(data, column, args) > 0 {
+		return true
+	}
+
+	return v.required(data, column)
+}
+
+// matchAny report whether the value of other equals one of the values
+func matchAny(data map[string]interface{}, other string, values []interface{}) bool {
+
+	value, ok := Lookup(data, other)
+	if !ok {
+		return false
+	}
+
+	val := ToString(value)
+	for _, v := range values {
+		if val == ToString(v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countPresent count the filled columns of others
+func (v *Validate) countPresent(data map[string]interface{}, column string, others []interface{}) int {
+
+	n := 0
+	for _, other := range others {
+		if v.required(data, relative(column, ToString(other))) {
+			n++
+		}
+	}
+
+	return n
+}
+
 func (v *Validate) alpha(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -111,7 +296,7 @@ func (v *Validate) alpha(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) alphaNumeric(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -121,7 +306,7 @@ func (v *Validate) alphaNumeric(data map[string]interface{}, column string, args
 
 func (v *Validate) alphaDash(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -131,7 +316,7 @@ func (v *Validate) alphaDash(data map[string]interface{}, column string, args ..
 
 func (v *Validate) between(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -164,7 +349,7 @@ func (v *Validate) between(data map[string]interface{}, column string, args ...i
 
 func (v *Validate) bool(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -179,7 +364,7 @@ func (v *Validate) bool(data map[string]interface{}, column string, args ...inte
 
 func (v *Validate) float(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -189,7 +374,7 @@ func (v *Validate) float(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) timeBefore(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -213,7 +398,7 @@ func (v *Validate) timeBefore(data map[string]interface{}, column string, args .
 
 func (v *Validate) timeAfter(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -237,7 +422,7 @@ func (v *Validate) timeAfter(data map[string]interface{}, column string, args ..
 
 func (v *Validate) equal(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -251,7 +436,7 @@ func (v *Validate) equal(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) different(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -265,35 +450,35 @@ func (v *Validate) different(data map[string]interface{}, column string, args ..
 
 func (v *Validate) equalWithColumn(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
 
-	if len(args) < 3 {
+	if len(args) < 1 {
 		return false
 	}
 
-	return ToString(value) == ToString(args[2])
+	return ToString(value) == ToString(args[0])
 }
 
 func (v *Validate) differentWithColumn(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
 
-	if len(args) < 3 {
+	if len(args) < 1 {
 		return false
 	}
 
-	return ToString(value) != ToString(args[2])
+	return ToString(value) != ToString(args[0])
 }
 
 func (v *Validate) in(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -315,7 +500,7 @@ func (v *Validate) in(data map[string]interface{}, column string, args ...interf
 
 func (v *Validate) integer(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -325,7 +510,7 @@ func (v *Validate) integer(data map[string]interface{}, column string, args ...i
 
 func (v *Validate) ip(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -335,7 +520,7 @@ func (v *Validate) ip(data map[string]interface{}, column string, args ...interf
 
 func (v *Validate) notIn(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -355,9 +540,18 @@ func (v *Validate) notIn(data map[string]interface{}, column string, args ...int
 	return true
 }
 
+// lengthArg convert the length argument, integral floats such as 4.0 from parsed or JSON input are accepted
+func lengthArg(arg interface{}) (int64, error) {
+	n, err := toInt64(arg)
+	if err != nil {
+		return 0, err
+	}
+	return n.(int64), nil
+}
+
 func (v *Validate) length(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -366,7 +560,7 @@ func (v *Validate) length(data map[string]interface{}, column string, args ...in
 		return false
 	}
 
-	length, err := ToInt(args[0])
+	length, err := lengthArg(args[0])
 	if err != nil {
 		return false
 	}
@@ -376,7 +570,7 @@ func (v *Validate) length(data map[string]interface{}, column string, args ...in
 
 func (v *Validate) lengthMax(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -385,7 +579,7 @@ func (v *Validate) lengthMax(data map[string]interface{}, column string, args ..
 		return false
 	}
 
-	length, err := ToInt(args[0])
+	length, err := lengthArg(args[0])
 	if err != nil {
 		return false
 	}
@@ -395,7 +589,7 @@ func (v *Validate) lengthMax(data map[string]interface{}, column string, args ..
 
 func (v *Validate) lengthMin(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -404,7 +598,7 @@ func (v *Validate) lengthMin(data map[string]interface{}, column string, args ..
 		return false
 	}
 
-	length, err := ToInt(args[0])
+	length, err := lengthArg(args[0])
 	if err != nil {
 		return false
 	}
@@ -414,7 +608,7 @@ func (v *Validate) lengthMin(data map[string]interface{}, column string, args ..
 
 func (v *Validate) betweenLen(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -423,12 +617,12 @@ func (v *Validate) betweenLen(data map[string]interface{}, column string, args .
 		return false
 	}
 
-	startLen, err := ToInt(args[0])
+	startLen, err := lengthArg(args[0])
 	if err != nil {
 		return false
 	}
 
-	endLen, err := ToInt(args[1])
+	endLen, err := lengthArg(args[1])
 	if err != nil {
 		return false
 	}
@@ -440,7 +634,7 @@ func (v *Validate) betweenLen(data map[string]interface{}, column string, args .
 
 func (v *Validate) max(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -464,7 +658,7 @@ func (v *Validate) max(data map[string]interface{}, column string, args ...inter
 
 func (v *Validate) min(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -486,9 +680,57 @@ func (v *Validate) min(data map[string]interface{}, column string, args ...inter
 	return val >= min
 }
 
+func (v *Validate) greaterThan(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	value, ok := Lookup(data, column)
+	if !ok {
+		return true
+	}
+
+	if len(args) < 1 {
+		return false
+	}
+
+	val, err := ToFloat(value)
+	if err != nil {
+		return false
+	}
+
+	min, err := ToFloat(args[0])
+	if err != nil {
+		return false
+	}
+
+	return val > min
+}
+
+func (v *Validate) lessThan(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	value, ok := Lookup(data, column)
+	if !ok {
+		return true
+	}
+
+	if len(args) < 1 {
+		return false
+	}
+
+	val, err := ToFloat(value)
+	if err != nil {
+		return false
+	}
+
+	max, err := ToFloat(args[0])
+	if err != nil {
+		return false
+	}
+
+	return val < max
+}
+
 func (v *Validate) money(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -498,23 +740,44 @@ func (v *Validate) money(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) regexp(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
 
-	if len(args) < 1 {
+	// the pattern is compiled by Rule.Regexp, a missing or invalid pattern fails
+	if len(args) == 0 {
+		return false
+	}
+	rxp, ok := args[0].(*regexp.Regexp)
+	if !ok {
 		return false
 	}
 
-	rxp := ToString(args[1])
+	return rxp.MatchString(ToString(value))
+}
+
+func (v *Validate) notRegexp(data map[string]interface{}, column string, args ...interface{}) bool {
+
+	value, ok := Lookup(data, column)
+	if !ok {
+		return true
+	}
+
+	if len(args) == 0 {
+		return false
+	}
+	rxp, ok := args[0].(*regexp.Regexp)
+	if !ok {
+		return false
+	}
 
-	return regexp.MustCompile(rxp).MatchString(ToString(value))
+	return !rxp.MatchString(ToString(value))
 }
 
 func (v *Validate) username(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -524,7 +787,7 @@ func (v *Validate) username(data map[string]interface{}, column string, args ...
 
 func (v *Validate) host(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -534,7 +797,7 @@ func (v *Validate) host(data map[string]interface{}, column string, args ...inte
 
 func (v *Validate) email(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -544,7 +807,7 @@ func (v *Validate) email(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) creditCard(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -554,7 +817,7 @@ func (v *Validate) creditCard(data map[string]interface{}, column string, args .
 
 func (v *Validate) numeric(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -564,7 +827,7 @@ func (v *Validate) numeric(data map[string]interface{}, column string, args ...i
 
 func (v *Validate) hexColor(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -574,7 +837,7 @@ func (v *Validate) hexColor(data map[string]interface{}, column string, args ...
 
 func (v *Validate) rgbColor(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -584,7 +847,7 @@ func (v *Validate) rgbColor(data map[string]interface{}, column string, args ...
 
 func (v *Validate) ascii(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -594,7 +857,7 @@ func (v *Validate) ascii(data map[string]interface{}, column string, args ...int
 
 func (v *Validate) base64(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -604,7 +867,7 @@ func (v *Validate) base64(data map[string]interface{}, column string, args ...in
 
 func (v *Validate) dnsName(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
@@ -614,10 +877,43 @@ func (v *Validate) dnsName(data map[string]interface{}, column string, args ...i
 
 func (v *Validate) url(data map[string]interface{}, column string, args ...interface{}) bool {
 
-	value, ok := data[column]
+	value, ok := Lookup(data, column)
 	if !ok {
 		return true
 	}
 
 	return rxpURL.MatchString(ToString(value))
 }
+
+// isNil report whether the value is nil or a nil pointer
+func isNil(value interface{}) bool {
+
+	if value == nil {
+		return true
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return val.IsNil()
+	}
+
+	return false
+}
+
+// isEmpty report whether the value is nil, an empty string, an empty slice or an empty map,
+// a string of spaces is not empty, use Trim before Required to reject it
+func isEmpty(value interface{}) bool {
+
+	if isNil(value) {
+		return true
+	}
+
+	val := reflect.ValueOf(value)
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return val.Len() == 0
+	}
+
+	return false
+}
@@ -1,13 +1,14 @@
 package govalidate
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 )
 
-var (
-	v *Validate
-)
-
 func TestValidate(t *testing.T) {
 
 	t.Parallel()
@@ -18,7 +19,7 @@ func TestValidate(t *testing.T) {
 		"status":   false,
 	}
 
-	v = New()
+	v := New()
 	v.AddColumn("username", "").Required("").AlphaNumeric("").Length(4, "")
 	v.AddColumn("password", "").Required("").AlphaDash("")
 	v.AddColumn("status", "").Required("").Bool("")
@@ -35,7 +36,7 @@ func TestRequired(t *testing.T) {
 	t.Parallel()
 
 	data := M{"user": "test"}
-	v = New()
+	v := New()
 	v.AddColumn("username", "username").Required("")
 	result := v.Validate(data)
 
@@ -101,7 +102,7 @@ func TestAlpha(t *testing.T) {
 		{M{"t1": "++"}, false},
 		{M{"t1": "+1"}, false},
 	}
-	v = New()
+	v := New()
 	for _, test := range tests {
 		v.AddColumn("t1", "").Alpha("")
 		result := v.Validate(test.value)
@@ -169,7 +170,7 @@ func TestAlphaNumeric(t *testing.T) {
 		{M{"t1": "++"}, false},
 		{M{"t1": "+1"}, false},
 	}
-	v = New()
+	v := New()
 	for _, test := range tests {
 		v.AddColumn("t1", "").AlphaNumeric("")
 		result := v.Validate(test.value)
@@ -236,7 +237,7 @@ func TestAlphaDash(t *testing.T) {
 		{M{"t1": "++"}, false},
 		{M{"t1": "+1"}, false},
 	}
-	v = New()
+	v := New()
 	for _, test := range tests {
 		v.AddColumn("t1", "").AlphaDash("")
 		result := v.Validate(test.value)
@@ -268,7 +269,7 @@ func TestBetween(t *testing.T) {
 		{M{"t1": "-1"}, -1, 1, true},
 		{M{"t1": "aa"}, -1, 1, false},
 	}
-	v = New()
+	v := New()
 	for _, test := range tests {
 		v.AddColumn("t1", "").Between(test.min, test.max, "")
 		result := v.Validate(test.value)
@@ -277,3 +278,1278 @@ func TestBetween(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateAll(t *testing.T) {
+
+	t.Parallel()
+
+	data := M{
+		"username": "te st",
+		"password": "12 345",
+		"status":   "none",
+	}
+
+	v := New()
+	v.AddColumn("username", "").AlphaNumeric("alphaNumeric").Length(4, "length")
+	v.AddColumn("password", "").AlphaDash("alphaDash")
+	v.AddColumn("status", "").Bool("bool")
+
+	if v.ValidateAll(data) != false {
+		t.Fatal("Expected ValidateAll to be false")
+	}
+
+	errs := v.Errors()
+	if len(errs) != 4 {
+		t.Fatalf("Expected 4 errors, got %d", len(errs))
+	}
+
+	if fields := errs.Fields(); len(fields) != 3 || fields[0] != "username" || fields[2] != "status" {
+		t.Errorf("Unexpected fields %v", fields)
+	}
+
+	if e := errs.First("username"); e == nil || e.GetRule() != "alphaNumeric" {
+		t.Errorf("Unexpected first error %v", e)
+	}
+
+	if n := len(errs.Group()["username"]); n != 2 {
+		t.Errorf("Expected 2 username errors, got %d", n)
+	}
+
+	if v.Error() != errs[0] {
+		t.Error("Expected Error() to be the first collected error")
+	}
+
+	v.SetMaxErrors(2)
+	v.ValidateAll(data)
+	if n := len(v.Errors()); n != 2 {
+		t.Errorf("Expected 2 errors with max, got %d", n)
+	}
+
+	v.Validate(data)
+	if n := len(v.Errors()); n != 1 {
+		t.Errorf("Expected Validate to stop at the first error, got %d", n)
+	}
+}
+
+func TestErrors(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "").Required("username is required")
+	v.AddColumn("status", "").Bool("")
+
+	if v.ValidateAll(M{"status": "none"}) != false {
+		t.Fatal("Expected ValidateAll to be false")
+	}
+
+	err := v.Errors().Err()
+	wrapped := fmt.Errorf("create user: %w", err)
+
+	if !errors.Is(wrapped, ErrRequired) || !errors.Is(wrapped, ErrBool) {
+		t.Errorf("Expected %v to match the required and bool sentinels", wrapped)
+	}
+
+	if errors.Is(wrapped, ErrEmail) {
+		t.Errorf("Expected %v not to match the email sentinel", wrapped)
+	}
+
+	var e *Error
+	if !errors.As(wrapped, &e) || e.GetField() != "username" {
+		t.Errorf("Expected errors.As to find the username error, got %v", e)
+	}
+
+	if err.Error() != "username is required; status只能是布尔型" {
+		t.Errorf("Unexpected error text %q", err.Error())
+	}
+
+	if Errors(nil).Err() != nil {
+		t.Error("Expected empty Errors to be a nil error")
+	}
+}
+
+func TestCustomRule(t *testing.T) {
+
+	t.Parallel()
+
+	prefix := func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, ok := data[column]
+		if !ok {
+			return true
+		}
+		return len(args) > 0 && strings.HasPrefix(ToString(value), ToString(args[0]))
+	}
+
+	RegisterRule("testSku", func(data map[string]interface{}, column string, args ...interface{}) bool {
+		return len(ToString(data[column])) == 8
+	})
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		rule     string
+	}{
+		{M{"sku": "SKU-0001"}, true, ""},
+		{M{"sku": "ABC-0001"}, false, "prefix"},
+		{M{"sku": "SKU-01"}, false, "testSku"},
+		{M{"sku": "SKU-0001", "tenant": "T9"}, false, "tenant"},
+	}
+
+	v := New()
+	v.RegisterRule("tenant", func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, ok := data[column]
+		return !ok || ToString(value) == "T1"
+	})
+	v.AddColumn("sku", "").Custom("prefix", prefix, "", "SKU-").Use("testSku", "")
+	v.AddColumn("tenant", "").Use("tenant", "")
+
+	schema := v.Schema()
+
+	for _, test := range tests {
+		result := schema.Validate(test.value)
+		if result.Valid() != test.expected {
+			t.Error(test.value, test.expected, result.Valid())
+			continue
+		}
+		if !test.expected {
+			if rule := result.FirstError().GetRule(); rule != test.rule {
+				t.Errorf("Expected rule %q, got %q", test.rule, rule)
+			}
+			if !errors.Is(result.FirstError(), ErrRule(test.rule)) {
+				t.Errorf("Expected %v to match ErrRule(%q)", result.FirstError(), test.rule)
+			}
+		}
+	}
+
+	v = New()
+	v.AddColumn("sku", "").Use("notRegistered", "")
+	if v.Validate(M{"sku": "SKU-0001"}) != false {
+		t.Error("Expected unregistered rule to fail")
+	}
+}
+
+func TestNestedColumn(t *testing.T) {
+
+	t.Parallel()
+
+	type contact struct {
+		Email string `json:"email"`
+		Phone string
+	}
+
+	type profile struct {
+		Contact *contact `json:"contact"`
+	}
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		field    string
+	}{
+		{M{"address": M{"city": "Beijing"}, "profile": profile{&contact{"a@b.cn", "123"}}}, true, ""},
+		{M{"address": map[string]interface{}{"city": "Bei jing"}, "profile": profile{&contact{"a@b.cn", "123"}}}, false, "address.city"},
+		{M{"address": M{"city": "Beijing"}, "profile": M{"contact": M{"email": "a.b.cn"}}}, false, "profile.contact.email"},
+		{M{"address": M{"city": "Beijing"}, "profile": &profile{}}, false, "profile.contact.email"},
+		{M{"address": "Beijing", "profile": profile{&contact{"a@b.cn", "abc"}}}, false, "address.city"},
+	}
+
+	v := New()
+	v.AddColumn("address.city", "").Required("").Alpha("")
+	v.AddColumn("profile.contact.email", "").Required("").Email("")
+	v.AddColumn("profile.contact.Phone", "").Numeric("")
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetField() != test.field {
+			t.Errorf("Expected field %q, got %q", test.field, v.Error().GetField())
+		}
+	}
+
+	input := M{"address": M{"city": "Beijing", "zip": "100000"}, "profile": profile{&contact{"a@b.cn", "123"}}}
+	v.Validate(input)
+
+	data := v.GetData()
+	city, _ := Lookup(data, "address.city")
+	email, _ := Lookup(data, "profile.contact.email")
+	if city != "Beijing" || email != "a@b.cn" {
+		t.Errorf("Unexpected nested data %v", data)
+	}
+	if _, ok := Lookup(data, "address.zip"); ok {
+		t.Errorf("Expected undeclared column to be dropped, got %v", data)
+	}
+	if len(input["address"].(M)) != 2 {
+		t.Errorf("Expected input to be unchanged, got %v", input)
+	}
+}
+
+func TestFlatDottedKey(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		field    string
+	}{
+		{M{"user.name": "tester"}, true, ""},
+		{M{"user.name": "te-ster"}, false, "user.name"},
+		{M{"user": M{"name": "tester"}}, true, ""},
+		{M{"user.name": "tester", "user": M{"name": "te-ster"}}, true, ""},
+		{M{}, false, "user.name"},
+	}
+
+	v := New()
+	v.AddColumn("user.name", "").Trim().Required("").Alpha("")
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetField() != test.field {
+			t.Errorf("Expected field %q, got %q", test.field, v.Error().GetField())
+		}
+	}
+
+	// the flat key keeps its shape in the validated data, also with filters and unknown keys
+	v.SetUnknownMode(UnknownReject).AllowKeys("x.id")
+	if !v.Validate(M{"user.name": " tester ", "x.id": 1}) {
+		t.Fatal(v.Error())
+	}
+	if data := v.GetData(); data["user.name"] != "tester" || data["x.id"] != 1 || len(data) != 2 {
+		t.Errorf("Unexpected data %v", data)
+	}
+	if v.Validate(M{"user.name": "tester", "x.name": 1}) || v.Error().GetField() != "x.name" {
+		t.Errorf("Expected the flat unknown key to fail, got %v", v.Error())
+	}
+}
+
+func TestWildcardColumn(t *testing.T) {
+
+	t.Parallel()
+
+	type item struct {
+		Sku string `json:"sku"`
+	}
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		field    string
+	}{
+		{M{"items": []interface{}{M{"sku": "A1"}, M{"sku": "B2"}}, "tags": []string{"x", "y"}}, true, ""},
+		{M{"items": []item{{"A1"}, {"B2"}, {"C3"}, {"D-4"}}, "tags": []string{"x"}}, false, "items.3.sku"},
+		{M{"items": []interface{}{M{"sku": "A1"}, M{}}}, false, "items.1.sku"},
+		{M{"items": []interface{}{M{"sku": "A1"}}, "tags": []string{"x", "y1"}}, false, "tags.1"},
+		{M{"items": []interface{}{}, "tags": []string{"x"}}, false, "items"},
+		{M{"tags": []string{"x"}}, false, "items"},
+		{M{"items": []interface{}{M{"sku": "A1"}}}, true, ""},
+	}
+
+	v := New()
+	v.AddColumn("items[*].sku", "").Required("").AlphaNumeric("")
+	v.AddColumn("tags.*", "").Alpha("")
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetField() != test.field {
+			t.Errorf("Expected field %q, got %q", test.field, v.Error().GetField())
+		}
+	}
+
+	v.Validate(M{"items": []item{{"A1"}, {"B2"}}, "tags": []string{"x"}})
+
+	data := v.GetData()
+	items, ok := data["items"].([]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("Unexpected items %v", data["items"])
+	}
+	if sku, _ := Lookup(data, "items.1.sku"); sku != "B2" {
+		t.Errorf("Expected items.1.sku to be B2, got %v", sku)
+	}
+	if tag, _ := Lookup(data, "tags.0"); tag != "x" {
+		t.Errorf("Expected tags.0 to be x, got %v", tag)
+	}
+}
+
+func TestWildcardMissingParent(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []struct {
+		build  func(v *Validate)
+		value  M
+		fields []string
+	}{
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").Required("") }, M{}, []string{"items"}},
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").Required("") }, M{"items": nil}, []string{"items"}},
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").Required("") }, M{"items": []M{}}, []string{"items"}},
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").Present("") }, M{"items": []M{}}, nil},
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").Present("") }, M{}, []string{"items"}},
+		{func(v *Validate) { v.AddColumn("items.*.sku", "").AlphaNumeric("") }, M{}, nil},
+		{func(v *Validate) {
+			v.AddColumn("items.*.sku", "").When(IfPresent("coupon"), func(r *Rule) { r.Required("") })
+		}, M{}, nil},
+		{func(v *Validate) {
+			v.AddColumn("items.*.sku", "").Required("")
+			v.AddColumn("items.*.qty", "").Present("")
+		}, M{}, []string{"items"}},
+		{func(v *Validate) { v.AddColumn("items.*.tags.*", "").Required("") },
+			M{"items": []M{{"tags": []string{"x"}}, {}, {"tags": []string{}}}}, []string{"items.1.tags", "items.2.tags"}},
+	}
+
+	for _, test := range tests {
+		for _, n := range []int{1, 4} {
+			v := New().SetConcurrency(n)
+			test.build(v)
+			v.ValidateAll(test.value)
+
+			var fields []string
+			for _, e := range v.Errors() {
+				fields = append(fields, e.GetField())
+			}
+			if !reflect.DeepEqual(fields, test.fields) {
+				t.Errorf("%v with concurrency %d: expected %v, got %v", test.value, n, test.fields, fields)
+			}
+		}
+	}
+}
+
+func TestWildcardFailedElements(t *testing.T) {
+
+	t.Parallel()
+
+	for _, n := range []int{1, 4} {
+
+		v := New().SetConcurrency(n)
+		v.AddColumn("items.*.sku", "").Required("").AlphaNumeric("")
+		v.AddColumn("items.*.qty", "").Integer("")
+
+		input := M{"items": []interface{}{M{"sku": "A1", "qty": 1}, M{"sku": "B-2", "qty": "x"}, M{"sku": "C3", "qty": 3}}}
+		if v.ValidateAll(input) {
+			t.Fatal("Expected items.1 to fail")
+		}
+
+		// the failed element stays nil, so the indexes match the input and the error fields
+		items, _ := v.GetData()["items"].([]interface{})
+		expected := []interface{}{map[string]interface{}{"sku": "A1", "qty": 1}, nil, map[string]interface{}{"sku": "C3", "qty": 3}}
+		if !reflect.DeepEqual(items, expected) {
+			t.Errorf("Concurrency %d: expected %v, got %v", n, expected, items)
+		}
+
+		result := v.Schema().ValidateAll(input)
+		if result.FirstError().GetField() != "items.1.sku" || result.String("items.1.sku") != "" || result.String("items.2.sku") != "C3" {
+			t.Errorf("Concurrency %d: unexpected result %v %v", n, result.Errors(), result.GetData())
+		}
+		if len(input["items"].([]interface{})) != 3 {
+			t.Errorf("Expected input to be unchanged, got %v", input)
+		}
+	}
+}
+
+func TestAddColumnRules(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.RegisterRule("prefix", func(data map[string]interface{}, column string, args ...interface{}) bool {
+		value, _ := Lookup(data, column)
+		return len(args) == 1 && strings.HasPrefix(ToString(value), ToString(args[0]))
+	})
+
+	columns := []struct {
+		name  string
+		rules string
+	}{
+		{"username", "required|alphaNumeric|length:4"},
+		{"age", "required | integer | between:1,150"},
+		{"status", "in:a,b,c"},
+		{"code", "alpha|lengthMax:3|prefix:ab"},
+	}
+
+	for _, c := range columns {
+		if _, err := v.AddColumnRules(c.name, "", c.rules); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		rule     string
+	}{
+		{M{"username": "test", "age": "18", "status": "b", "code": "abc"}, true, ""},
+		{M{"username": "tester", "age": "18"}, false, "length"},
+		{M{"username": "test", "age": "200"}, false, "between"},
+		{M{"username": "test", "age": "18", "status": "d"}, false, "in"},
+		{M{"username": "test", "age": "18", "code": "bcd"}, false, "prefix"},
+	}
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetRule() != test.rule {
+			t.Errorf("Expected rule %q, got %q", test.rule, v.Error().GetRule())
+		}
+	}
+
+	var errs = []*struct {
+		rules string
+		rule  string
+	}{
+		{"required|unknown", "unknown"},
+		{"length", "length"},
+		{"length:", "length:"},
+		{"between:1", "between:1"},
+		{"between:1,a", "between:1,a"},
+		{"in", "in"},
+		{"email:1", "email:1"},
+		{"regexp:[a-", "regexp:[a-"},
+	}
+
+	for _, test := range errs {
+		v := New()
+		_, err := v.AddColumnRules("t1", "", test.rules)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Rule != test.rule || pe.Column != "t1" {
+			t.Errorf("Expected parse error of %q for %q, got %v", test.rule, test.rules, err)
+		}
+		if len(v.columns) != 0 || !v.Validate(M{}) {
+			t.Errorf("Expected no column to be added for %q, got %v", test.rules, v.columns)
+		}
+	}
+}
+
+func TestRegexp(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("code", "").Regexp(`^[a-z]{2}\d+$`, "")
+	v.AddColumn("sku", "").Regexp(regexp.MustCompile(`^SKU-`), "").NotRegexp(`-0+$`, "")
+	if _, err := v.AddColumnRules("tag", "", "regexp:^[a-z]{1,3}$|notRegexp:^x+$"); err != nil {
+		t.Fatal(err)
+	}
+
+	schema, err := v.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []*struct {
+		value M
+		rule  string
+	}{
+		{M{"code": "ab12", "sku": "SKU-12", "tag": "go"}, ""},
+		{M{"code": "ab"}, "regexp"},
+		{M{"sku": "sku-12"}, "regexp"},
+		{M{"sku": "SKU-000"}, "notRegexp"},
+		{M{"tag": "golang"}, "regexp"},
+		{M{"tag": "xxx"}, "notRegexp"},
+	}
+
+	for _, test := range tests {
+		result := schema.Validate(test.value)
+		if test.rule == "" && !result.Valid() {
+			t.Error(test.value, result.FirstError())
+		}
+		if test.rule != "" && (result.Valid() || result.FirstError().GetRule() != test.rule) {
+			t.Errorf("Expected %v to fail %q, got %v", test.value, test.rule, result.FirstError())
+		}
+	}
+
+	v = New()
+	v.AddColumn("code", "").Regexp(`[a-`, "")
+	v.AddColumn("sku", "").NotRegexp(10, "")
+
+	var be BuildErrors
+	if _, err := v.Build(); !errors.As(err, &be) || len(be) != 2 || be[0].Column != "code" || be[1].Rule != "notRegexp" {
+		t.Errorf("Expected build errors, got %v", err)
+	}
+
+	if v.Validate(M{"code": "ab"}) || v.Error().GetRule() != "regexp" || !errors.As(v.Err(), &be) {
+		t.Errorf("Expected the invalid pattern to stop the validation, got %v", v.Error())
+	}
+
+	func() {
+		defer func() {
+			if r, ok := recover().(BuildErrors); !ok || len(r) != 2 {
+				t.Errorf("Expected Schema to panic with the build errors, got %v", r)
+			}
+		}()
+		v.Schema()
+	}()
+
+	v = New()
+	v.AddColumn("code", "").Custom("regexp", (&Validate{}).regexp, "")
+	v.AddColumn("sku", "").Custom("notRegexp", (&Validate{}).notRegexp, "")
+	if v.ValidateAll(M{"code": "ab", "sku": "a1"}) || len(v.Errors()) != 2 {
+		t.Errorf("Expected the rules without pattern to fail, got %v", v.Errors())
+	}
+}
+
+func TestBuild(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "登录账户").Required("").Length(4, "")
+	v.AddColumn("age", "").Between(1, 150, "").Min(Ref("min_age"), "")
+	v.AddColumn("start_at", "").TimeBefore("2030-01-01T00:00:00Z", "")
+	v.AddColumn("code", "").When(IfPresent("type"), func(r *Rule) { r.LengthMin(6, "") }).LengthMax(4, "")
+	v.AddColumn("username", "")
+	v.AddColumn("nickname", "").Custom("betweenLen", (&Validate{}).betweenLen, "", 2.0, float64(8))
+
+	schema, err := v.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := schema.Validate(M{"username": "test", "nickname": "abc"}); !result.Valid() {
+		t.Errorf("Expected integral float lengths to apply, got %v", result.FirstError())
+	}
+
+	var tests = []*struct {
+		build  func(v *Validate)
+		column string
+		rule   string
+	}{
+		{func(v *Validate) { v.AddColumn("age", "").Between(10, 1, "") }, "age", "between"},
+		{func(v *Validate) { v.AddColumn("age", "").Custom("between", (&Validate{}).between, "", "a", 10) }, "age", "between"},
+		{func(v *Validate) { v.AddColumn("age", "").Custom("max", (&Validate{}).max, "", "a") }, "age", "max"},
+		{func(v *Validate) { v.AddColumn("name", "").Length(-1, "") }, "name", "length"},
+		{func(v *Validate) { v.AddColumn("name", "").BetweenLen(8, 4, "") }, "name", "betweenLen"},
+		{func(v *Validate) { v.AddColumn("name", "").Length(4, "").LengthMin(5, "") }, "name", "lengthMin"},
+		{func(v *Validate) { v.AddColumn("name", "").LengthMax(3, "").BetweenLen(4, 8, "") }, "name", "betweenLen"},
+		{func(v *Validate) { v.AddColumn("age", "").Min(18, "").Between(1, 10, "") }, "age", "between"},
+		{func(v *Validate) { v.AddColumn("age", "").Max(10, "").Min(18, "") }, "age", "min"},
+		{func(v *Validate) { v.AddColumn("start_at", "").TimeAfter("tomorrow", "") }, "start_at", "dateAfter"},
+		{func(v *Validate) { v.AddColumn("status", "").In(nil, "") }, "status", "in"},
+		{func(v *Validate) { v.AddColumn("title", "").RequiredIf("need_invoice", nil, "") }, "title", "requiredIf"},
+		{func(v *Validate) { v.AddColumn("sku", "").Use("unregistered", "") }, "sku", "unregistered"},
+		{func(v *Validate) { v.AddColumn("name", "").Custom("length", (&Validate{}).length, "", 4.5) }, "name", "length"},
+		{func(v *Validate) { v.AddColumn("name", "账户"); v.AddColumn("name", "名称") }, "name", ""},
+		{func(v *Validate) {
+			v.AddColumn("name", "账户")
+			v.AddColumn("name", "名称")
+			v.AddColumn("name", "名称")
+		}, "name", ""},
+	}
+
+	for _, test := range tests {
+		v := New()
+		test.build(v)
+		schema, err := v.Build()
+		var be BuildErrors
+		if schema != nil || !errors.As(err, &be) || len(be) != 1 || be[0].Column != test.column || be[0].Rule != test.rule {
+			t.Errorf("Expected build error of %s %s, got %v", test.column, test.rule, err)
+		}
+	}
+
+	v = New()
+	v.AddColumn("age", "").Between(10, 1, "").Length(-1, "")
+	v.AddColumn("name", "").LengthMax(3, "").LengthMin(4, "")
+	if _, err := v.Build(); err == nil || strings.Count(err.Error(), "govalidate:") != 3 {
+		t.Errorf("Expected every misconfigured rule, got %v", err)
+	}
+}
+
+func TestRequiredConditional(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		rule     func(r *Rule) *Rule
+		value    M
+		expected bool
+	}{
+		{func(r *Rule) *Rule { return r.RequiredIf("need_invoice", []interface{}{true}, "") }, M{"need_invoice": true, "t1": "ACME"}, true},
+		{func(r *Rule) *Rule { return r.RequiredIf("need_invoice", []interface{}{true}, "") }, M{"need_invoice": "false"}, true},
+		{func(r *Rule) *Rule { return r.RequiredIf("need_invoice", []interface{}{true}, "") }, M{}, true},
+		{func(r *Rule) *Rule { return r.RequiredIf("need_invoice", []interface{}{true}, "") }, M{"need_invoice": true}, false},
+		{func(r *Rule) *Rule { return r.RequiredUnless("pay_method", []interface{}{"card"}, "") }, M{"pay_method": "card"}, true},
+		{func(r *Rule) *Rule { return r.RequiredUnless("pay_method", []interface{}{"card"}, "") }, M{"pay_method": "cash"}, false},
+		{func(r *Rule) *Rule { return r.RequiredUnless("pay_method", []interface{}{"card"}, "") }, M{}, false},
+		{func(r *Rule) *Rule { return r.RequiredWith([]string{"tax_id", "vat"}, "") }, M{}, true},
+		{func(r *Rule) *Rule { return r.RequiredWith([]string{"tax_id", "vat"}, "") }, M{"vat": "1"}, false},
+		{func(r *Rule) *Rule { return r.RequiredWith([]string{"tax_id", "vat"}, "") }, M{"vat": "1", "t1": "ACME"}, true},
+		{func(r *Rule) *Rule { return r.RequiredWithAll([]string{"first", "last"}, "") }, M{"first": "a"}, true},
+		{func(r *Rule) *Rule { return r.RequiredWithAll([]string{"first", "last"}, "") }, M{"first": "a", "last": "b"}, false},
+		{func(r *Rule) *Rule { return r.RequiredWithout([]string{"first", "last"}, "") }, M{"first": "a", "last": "b"}, true},
+		{func(r *Rule) *Rule { return r.RequiredWithout([]string{"first", "last"}, "") }, M{"first": "a"}, false},
+		{func(r *Rule) *Rule { return r.RequiredWithoutAll([]string{"email", "phone"}, "") }, M{"phone": "1"}, true},
+		{func(r *Rule) *Rule { return r.RequiredWithoutAll([]string{"email", "phone"}, "") }, M{}, false},
+		{func(r *Rule) *Rule { return r.RequiredWithoutAll([]string{"email", "phone"}, "") }, M{"t1": "a@b.cn"}, true},
+	}
+
+	for _, test := range tests {
+		v := New()
+		r := test.rule(v.AddColumn("t1", ""))
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(r.item[0].name, test.value, test.expected, result)
+		}
+	}
+
+	v := New()
+	v.AddColumn("items.*.sku", "").RequiredIf("items.*.type", []interface{}{"sku"}, "")
+
+	if v.Validate(M{"items": []interface{}{M{"type": "sku", "sku": "A1"}, M{"type": "gift"}, M{"type": "sku"}}}) != false {
+		t.Fatal("Expected RequiredIf on the wildcard column to be false")
+	}
+	if field := v.Error().GetField(); field != "items.2.sku" {
+		t.Errorf("Expected field items.2.sku, got %q", field)
+	}
+}
+
+func TestWhenUnless(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		rule     string
+	}{
+		{M{"pay_method": "card", "card_number": "4111111111111111"}, true, ""},
+		{M{"pay_method": "card", "card_number": "1234"}, false, "creditCard"},
+		{M{"pay_method": "card"}, false, "required"},
+		{M{"pay_method": "cash", "card_number": "1234"}, true, ""},
+		{M{"pay_method": "cash", "coupon": "ab"}, true, ""},
+		{M{"pay_method": "cash", "coupon": "ab", "vip": true}, false, "lengthMin"},
+		{M{"pay_method": "card", "card_number": "4111111111111111", "coupon": "ab", "vip": 1}, false, "lengthMin"},
+		{M{"pay_method": "card", "card_number": "4111111111111111", "coupon": "abcd", "vip": 1}, false, "different"},
+	}
+
+	v := New()
+	v.AddColumn("card_number", "").When(IfEqual("pay_method", "card"), func(r *Rule) {
+		r.Required("").CreditCard("")
+	})
+	v.AddColumn("coupon", "").Unless(IfMissing("vip"), func(r *Rule) {
+		r.LengthMin(4, "").When(func(data M) bool { return data["pay_method"] == "card" }, func(r *Rule) {
+			r.Different("abcd", "")
+		})
+	})
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetRule() != test.rule {
+			t.Errorf("Expected rule %q, got %q", test.rule, v.Error().GetRule())
+		}
+	}
+
+	if !IfPresent("a", "b")(M{"a": 1, "b": nil}) || IfPresent("a", "b")(M{"a": 1}) {
+		t.Error("Unexpected IfPresent result")
+	}
+
+	v = New()
+	v.AddColumn("items.*.price", "").When(IfEqual("items.*.type", "paid"), func(r *Rule) {
+		r.Required("")
+	}).Unless(IfMissing("items.*.discount"), func(r *Rule) {
+		r.Max(100, "")
+	})
+
+	items := []interface{}{M{"type": "free"}, M{"type": "paid", "price": 120}, M{"type": "paid"}}
+	if v.ValidateAll(M{"items": items}) || fmt.Sprint(v.Errors().Fields()) != "[items.2.price]" {
+		t.Errorf("Expected the conditions to resolve per element, got %v", v.Errors())
+	}
+
+	items = []interface{}{M{"type": "paid", "price": 120, "discount": 10}, M{"type": "paid", "price": 120}}
+	if v.ValidateAll(M{"items": items}) || fmt.Sprint(v.Errors().Fields()) != "[items.0.price]" {
+		t.Errorf("Expected the conditions to resolve per element, got %v", v.Errors())
+	}
+
+	if !IfEqual("items.*.type", "paid")(M{"items": items}) || IfMissing("items.*.discount")(M{"items": items}) {
+		t.Error("Expected a wildcard condition to match any element")
+	}
+
+	var predicate func(M) bool = func(data M) bool { return data["vip"] == true }
+	v = New()
+	v.AddColumn("coupon", "").When(predicate, func(r *Rule) { r.Required("") })
+	if v.Validate(M{}) != true || v.Validate(M{"vip": true}) != false {
+		t.Error("Expected a plain predicate to be a condition")
+	}
+
+	for _, fn := range []func(r *Rule){
+		func(r *Rule) { r.Default(1) },
+		func(r *Rule) { r.DefaultFunc(func(data M) interface{} { return 1 }) },
+		func(r *Rule) { r.DefaultEmpty() },
+		func(r *Rule) { r.As(TypeInt64) },
+	} {
+		v = New()
+		v.AddColumn("coupon", "").When(IfPresent("vip"), fn)
+		if _, err := v.Build(); err == nil || !strings.Contains(err.Error(), "can't be conditional") {
+			t.Errorf("Expected a conditional default to be rejected, got %v", err)
+		}
+	}
+}
+
+func TestEmptyValues(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		rule    func(r *Rule) *Rule
+		missing bool
+		null    bool
+		empty   bool
+		filled  bool
+	}{
+		{func(r *Rule) *Rule { return r.Required("") }, false, false, false, true},
+		{func(r *Rule) *Rule { return r.Present("") }, false, true, true, true},
+		{func(r *Rule) *Rule { return r.Filled("") }, true, false, false, true},
+		{func(r *Rule) *Rule { return r.Nullable().Alpha("") }, true, true, false, false},
+		{func(r *Rule) *Rule { return r.Nullable().Required("") }, false, true, false, true},
+	}
+
+	for i, test := range tests {
+		for _, c := range []struct {
+			value    M
+			expected bool
+		}{
+			{M{}, test.missing},
+			{M{"t1": nil}, test.null},
+			{M{"t1": ""}, test.empty},
+			{M{"t1": "123"}, test.filled},
+		} {
+			v := New()
+			test.rule(v.AddColumn("t1", ""))
+			if result := v.Validate(c.value); result != c.expected {
+				t.Error(i, c.value, c.expected, result)
+			}
+		}
+	}
+
+	for _, value := range []interface{}{"", []interface{}{}, map[string]interface{}{}, M{}, (*int)(nil)} {
+		v := New()
+		v.AddColumn("t1", "").Required("")
+		if v.Validate(M{"t1": value}) != false {
+			t.Errorf("Expected Required to reject %#v", value)
+		}
+	}
+
+	for _, value := range []interface{}{false, 0, "0", "  ", []int{0}} {
+		v := New()
+		v.AddColumn("t1", "").Required("")
+		if v.Validate(M{"t1": value}) != true {
+			t.Errorf("Expected Required to accept %#v", value)
+		}
+	}
+}
+
+func TestEmptyMode(t *testing.T) {
+
+	t.Parallel()
+
+	for name, spec := range ruleSpecs {
+
+		var args []string
+		switch spec.args {
+		case -1:
+			args = []string{"t2", "1"}
+		default:
+			for i := 0; i < spec.args; i++ {
+				args = append(args, "1")
+			}
+		}
+
+		v := New()
+		if err := spec.build(v.AddColumn("t1", ""), args, ""); err != nil {
+			t.Fatal(name, err)
+		}
+
+		if v.AddColumn("t1", "").item[0].implicit || name == "filled" {
+			continue
+		}
+
+		for _, c := range []struct {
+			mode  EmptyMode
+			value M
+		}{
+			{EmptyValidate, M{"t2": "1"}},
+			{EmptySkip, M{"t2": "1"}},
+			{EmptySkip, M{"t1": "", "t2": "1"}},
+			{EmptySkip, M{"t1": nil, "t2": "1"}},
+			{EmptySkip, M{"t1": []interface{}{}, "t2": "1"}},
+		} {
+			v.SetEmptyMode(c.mode)
+			if result := v.Validate(c.value); result != true {
+				t.Error(name, c.mode, c.value, result)
+			}
+		}
+	}
+
+	v := New().SetEmptyMode(EmptySkip)
+	v.AddColumn("t1", "").Required("").Alpha("")
+	if v.Validate(M{"t1": ""}) != false || v.Error().GetRule() != "required" {
+		t.Error("Expected Required to reject empty value in EmptySkip mode")
+	}
+}
+
+func TestRef(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		value    M
+		expected bool
+		rule     string
+	}{
+		{M{"quantity": 3, "stock": 5, "start_at": "2020-01-01T00:00:00Z", "end_at": "2020-02-01T00:00:00Z", "password": "a1", "confirm": "a1"}, true, ""},
+		{M{"quantity": 6, "stock": 5}, false, "max"},
+		{M{"quantity": 6}, false, "max"},
+		{M{"start_at": "2020-03-01T00:00:00Z", "end_at": "2020-02-01T00:00:00Z"}, false, "dateAfter"},
+		{M{"password": "a1", "confirm": "a2"}, false, "equalWithColumn"},
+		{M{"password": "a1", "confirm": "a1", "username": "a1"}, false, "differentWithColumn"},
+		{M{"items": []interface{}{M{"qty": 1, "min": 1, "stock": 2}, M{"qty": 3, "min": 1, "stock": 2}}}, false, "between"},
+	}
+
+	v := New()
+	v.AddColumn("quantity", "").Max(Ref("stock"), "")
+	v.AddColumn("end_at", "").TimeAfter(Ref("start_at"), "")
+	v.AddColumn("confirm", "").EqualWithColumn("password", "")
+	v.AddColumn("username", "").DifferentWithColumn("password", "")
+	v.AddColumn("items.*.qty", "").Between(Ref("items.*.min"), Ref("items.*.stock"), "")
+
+	for _, test := range tests {
+		result := v.Validate(test.value)
+		if result != test.expected {
+			t.Error(test.value, test.expected, result)
+			continue
+		}
+		if !result && v.Error().GetRule() != test.rule {
+			t.Errorf("Expected rule %q, got %q", test.rule, v.Error().GetRule())
+		}
+	}
+
+	v.Validate(M{"items": []interface{}{M{"qty": 3, "min": 1, "stock": 2}}})
+	if args := v.Error().GetRuleArg().([]interface{}); v.Error().GetField() != "items.0.qty" || args[1] != 2 {
+		t.Errorf("Expected resolved args, got %v %v", v.Error().GetField(), args)
+	}
+
+	v = New()
+	v.AddColumn("discount", "").Between(0, Ref("price"), "")
+	if _, err := v.AddColumnRules("quantity", "", "max:$stock|betweenLen:1,$digits"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if !v.ValidateAll(M{"discount": 5, "price": 10, "quantity": 3, "stock": 5, "digits": 2}) {
+		t.Error(v.Errors())
+	}
+	if v.ValidateAll(M{"discount": 12, "price": 10, "quantity": 600, "stock": 5, "digits": 2}) || len(v.Errors()) != 3 {
+		t.Errorf("Expected the literal and Ref bounds to fail, got %v", v.Errors())
+	}
+
+	if _, err := New().AddColumnRules("quantity", "", "max:$"); err == nil {
+		t.Error("Expected an empty reference to be a parse error")
+	}
+
+	v = New()
+	v.AddColumn("quantity", "").Max("many", "")
+	if _, err := v.Build(); err == nil {
+		t.Error("Expected a literal bound which is not a number to fail Build")
+	}
+}
+
+func TestFilter(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		filter   func(r *Rule) *Rule
+		value    interface{}
+		expected interface{}
+	}{
+		{(*Rule).Trim, " Alice ", "Alice"},
+		{(*Rule).Lower, "ABC@Example.COM", "abc@example.com"},
+		{(*Rule).Upper, "abc", "ABC"},
+		{(*Rule).StripTags, "<b>bold</b> text<br/>", "bold text"},
+		{(*Rule).CollapseSpaces, "a  b \t\n c", "a b c"},
+		{(*Rule).ToInt, "18", int64(18)},
+		{(*Rule).ToInt, "abc", "abc"},
+		{(*Rule).ToFloat, "1.5", 1.5},
+		{(*Rule).ToBool, "true", true},
+		{(*Rule).Trim, 12, 12},
+		{func(r *Rule) *Rule {
+			return r.Filter(func(value interface{}) interface{} { return ToString(value) + "!" })
+		}, "hi", "hi!"},
+	}
+
+	for _, test := range tests {
+		v := New()
+		test.filter(v.AddColumn("t1", ""))
+		if !v.Validate(M{"t1": test.value}) {
+			t.Error(test.value, v.Error())
+			continue
+		}
+		if result := v.GetData()["t1"]; result != test.expected {
+			t.Errorf("Expected %#v to be %#v, got %#v", test.value, test.expected, result)
+		}
+	}
+
+	v := New()
+	v.AddColumn("username", "").Required("").Trim().Lower().AlphaNumeric("").LengthMax(5, "")
+	v.AddColumn("age", "").Integer("").ToInt().Between(1, 150, "")
+	v.AddColumn("items.*.sku", "").Trim().Upper().Equal("A1", "")
+
+	input := M{"username": "  Alice ", "age": "18", "items": []interface{}{M{"sku": " a1 "}}}
+	if !v.Validate(input) {
+		t.Fatal(v.Error())
+	}
+
+	data := v.GetData()
+	if sku, _ := Lookup(data, "items.0.sku"); data["username"] != "alice" || data["age"] != int64(18) || sku != "A1" {
+		t.Errorf("Unexpected filtered data %v", data)
+	}
+	if sku, _ := Lookup(input, "items.0.sku"); input["username"] != "  Alice " || sku != " a1 " {
+		t.Errorf("Expected input to be unchanged, got %v", input)
+	}
+
+	if v.Validate(M{"username": "  Alice Bob "}) != false || v.Error().GetFieldData() != "alice bob" {
+		t.Errorf("Expected error data to be the filtered value, got %v", v.Error().GetFieldData())
+	}
+
+	v = New()
+	v.AddColumn("password", "").Trim()
+	v.AddColumn("confirm", "").Equal(Ref("password"), "")
+	if !v.Validate(M{"password": " secret ", "confirm": " secret "}) {
+		t.Errorf("Expected the other columns to see the input value, got %v", v.Error())
+	}
+}
+
+func TestDefault(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("page_size", "").Default(20).Integer("").Between(1, 100, "")
+	v.AddColumn("status", "").Default("on").DefaultEmpty().In([]interface{}{"on", "off"}, "")
+	v.AddColumn("title", "").DefaultFunc(func(data M) interface{} { return ToString(data["name"]) + "'s" }).Required("")
+	v.AddColumn("remark", "").Default("").Present("")
+	v.AddColumn("items.*.qty", "").Default(1).Min(1, "")
+
+	var tests = []*struct {
+		value    M
+		expected M
+	}{
+		{M{"name": "Alice"}, M{"page_size": 20, "status": "on", "title": "Alice's", "remark": ""}},
+		{M{"name": "Alice", "page_size": 50, "status": "", "title": "T", "remark": nil}, M{"page_size": 50, "status": "on", "title": "T", "remark": nil}},
+	}
+
+	for _, test := range tests {
+		result := v.ValidateAll(test.value)
+		data := v.GetData()
+		if !result {
+			t.Error(test.value, v.Error())
+			continue
+		}
+		for key, expected := range test.expected {
+			if data[key] != expected {
+				t.Errorf("Expected %s to be %#v, got %#v", key, expected, data[key])
+			}
+		}
+	}
+
+	if v.Validate(M{"page_size": nil}) != false || v.Error().GetField() != "page_size" {
+		t.Errorf("Expected nil page_size without DefaultEmpty to fail, got %v", v.Error())
+	}
+
+	if !v.Validate(M{"items": []interface{}{M{}, M{"qty": 3}}}) {
+		t.Fatal(v.Error())
+	}
+	if qty, _ := Lookup(v.GetData(), "items.0.qty"); qty != 1 {
+		t.Errorf("Expected items.0.qty to default to 1, got %v", qty)
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+
+	t.Parallel()
+
+	data := M{
+		"username": "test",
+		"is_admin": true,
+		"address":  M{"city": "Beijing", "zip": "100000"},
+		"items":    []interface{}{M{"sku": "A1", "price": 1}},
+		"meta":     M{"trace": "abc"},
+		"x_source": "web",
+	}
+
+	columns := func(v *Validate) *Validate {
+		v.AddColumn("username", "").Required("")
+		v.AddColumn("address.city", "").Required("")
+		v.AddColumn("items.*.sku", "").Required("")
+		return v
+	}
+
+	v := columns(New())
+	if !v.Validate(data) {
+		t.Fatal(v.Error())
+	}
+	if _, ok := v.GetData()["is_admin"]; ok {
+		t.Errorf("Expected unknown keys to be stripped, got %v", v.GetData())
+	}
+
+	v = columns(New()).SetUnknownMode(UnknownReject)
+	if v.ValidateAll(data) != false {
+		t.Fatal("Expected unknown keys to be rejected")
+	}
+
+	expected := []string{"address.zip", "is_admin", "items.0.price", "meta", "x_source"}
+	if fields := v.Errors().Fields(); strings.Join(fields, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected unknown fields %v, got %v", expected, fields)
+	}
+	if !errors.Is(v.Errors().Err(), ErrUnknownField) {
+		t.Errorf("Expected %v to match ErrUnknownField", v.Errors())
+	}
+
+	v = columns(New()).SetUnknownMode(UnknownReject).AllowKeys("meta", "x_*", "items[*].price")
+	if v.ValidateAll(data) != false {
+		t.Fatal("Expected unknown keys to be rejected")
+	}
+	if fields := v.Errors().Fields(); strings.Join(fields, ",") != "address.zip,is_admin" {
+		t.Errorf("Unexpected unknown fields %v", fields)
+	}
+
+	v = columns(New()).SetUnknownMode(UnknownReject).AllowKeys("meta.*", "address.zip")
+	if v.ValidateAll(data) != false {
+		t.Fatal("Expected unknown keys to be rejected")
+	}
+	if fields := v.Errors().Fields(); strings.Join(fields, ",") != "is_admin,items.0.price,x_source" {
+		t.Errorf("Unexpected unknown fields %v", fields)
+	}
+	if trace, _ := Lookup(v.GetData(), "meta.trace"); trace != "abc" {
+		t.Errorf("Expected the keys inside meta to pass through, got %v", v.GetData())
+	}
+	if v.ValidateAll(M{"username": "test", "address": M{"city": "Beijing"}, "items": []M{{"sku": "A1"}}, "meta": "abc"}) || v.Errors().Fields()[0] != "meta" {
+		t.Errorf("Expected meta without keys to be rejected, got %v", v.Errors())
+	}
+
+	v = columns(New()).AllowKeys("meta")
+	if !v.Validate(data) {
+		t.Fatal(v.Error())
+	}
+	if trace, _ := Lookup(v.GetData(), "meta.trace"); trace != "abc" {
+		t.Errorf("Expected allowed key to pass through, got %v", v.GetData())
+	}
+	if _, ok := v.GetData()["x_source"]; ok {
+		t.Errorf("Expected not allowed key to be stripped, got %v", v.GetData())
+	}
+}
+
+func TestUnknownKeysDeclaredChildren(t *testing.T) {
+
+	t.Parallel()
+
+	data := M{"address": M{"city": "Beijing", "zip": "100000"}}
+
+	columns := func(v *Validate) *Validate {
+		v.AddColumn("address", "").Required("")
+		v.AddColumn("address.city", "").Required("")
+		return v
+	}
+
+	v := columns(New()).SetUnknownMode(UnknownReject)
+	if v.ValidateAll(data) != false {
+		t.Fatal("Expected the undeclared child of a column to be rejected")
+	}
+	if fields := v.Errors().Fields(); strings.Join(fields, ",") != "address.zip" {
+		t.Errorf("Unexpected unknown fields %v", fields)
+	}
+
+	v = columns(New()).SetUnknownMode(UnknownReject).AllowKeys("address.zip")
+	if !v.ValidateAll(data) {
+		t.Fatal(v.Errors())
+	}
+
+	v = columns(New())
+	if !v.ValidateAll(data) {
+		t.Fatal(v.Errors())
+	}
+	if zip, _ := Lookup(v.GetData(), "address.zip"); zip != "100000" {
+		t.Errorf("Expected the column value to be kept whole when stripping, got %v", v.GetData())
+	}
+
+	v = New().SetUnknownMode(UnknownReject)
+	v.AddColumn("address", "").Required("")
+	if !v.ValidateAll(data) {
+		t.Errorf("Expected the children of a column without declared children to pass, got %v", v.Errors())
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+
+	t.Parallel()
+
+	var tests = []*struct {
+		rule     func(r *Rule) *Rule
+		value    interface{}
+		expected string
+	}{
+		{func(r *Rule) *Rule { return r.BetweenLen(4, 8, "{alias}长度必须在{min}到{max}之间") }, "abc", "登录账户长度必须在4到8之间"},
+		{func(r *Rule) *Rule { return r.Equal("root", "{field} must equal {arg0}, got {value}") }, "admin", "username must equal root, got admin"},
+		{func(r *Rule) *Rule { return r.In([]interface{}{"a", "b"}, "{alias} must be one of {values}") }, "c", "登录账户 must be one of a,b"},
+		{func(r *Rule) *Rule { return r.Max(Ref("limit"), "{value} > {max}") }, 12, "12 > limit"},
+		{func(r *Rule) *Rule { return r.EqualWithColumn("password", "{alias} must equal {other}") }, "admin", "登录账户 must equal password"},
+		{func(r *Rule) *Rule { return r.Alpha("{alias} {unknown}") }, "1", "登录账户 {unknown}"},
+		{func(r *Rule) *Rule { return r.Alpha("plain message") }, "1", "plain message"},
+	}
+
+	for _, test := range tests {
+		v := New()
+		test.rule(v.AddColumn("username", "登录账户"))
+		if v.Validate(M{"username": test.value, "limit": 10, "password": "hunter2"}) {
+			t.Errorf("Expected %v to fail", test.value)
+			continue
+		}
+		if message := v.Error().GetErrorMessage(); message != test.expected {
+			t.Errorf("Expected message %q, got %q", test.expected, message)
+		}
+	}
+
+	v := New()
+	v.AddColumn("items.*.sku", "").Required("{alias} is required")
+	v.Validate(M{"items": []interface{}{M{}}})
+	if message := v.Error().Error(); message != "items.0.sku is required" {
+		t.Errorf("Expected the field as alias fallback, got %q", message)
+	}
+}
+
+func TestLocale(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("username", "登录账户").Required("")
+	v.AddColumn("age", "Age").Between(1, 150, "")
+	v.AddColumn("code", "Code").Custom("testLocaleCode", func(data map[string]interface{}, column string, args ...interface{}) bool {
+		return false
+	}, "")
+	v.AddColumn("nickname", "").Required("{alias} explicit")
+
+	schema := v.Schema()
+
+	var tests = []*struct {
+		schema   *Schema
+		value    M
+		expected string
+	}{
+		{schema, M{}, "登录账户是必须的"},
+		{schema.WithLocale(LocaleEn), M{}, "登录账户 is required"},
+		{schema.WithLocale("en-US"), M{"username": "a", "age": 200}, "Age must be between 1 and 150"},
+		{schema.WithLocale("fr"), M{"username": "a", "age": 200}, "Age必须在1到150之间"},
+		{schema.WithLocale("testLocale"), M{"username": "a", "age": 200}, "Age must be in [1, 150]"},
+		{schema.WithLocale("testLocale"), M{"username": "a", "age": 1}, "Code is not a valid code"},
+		{schema.WithLocale("en"), M{"username": "a", "age": 1}, ""},
+	}
+
+	if err := LoadMessages("testLocale", []byte(`{"between": "{alias} must be in [{min}, {max}]", "testLocaleCode": "{alias} is not a valid code"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range tests {
+		result := test.schema.Validate(test.value)
+		if result.Valid() {
+			t.Errorf("%d: Expected %v to fail", i, test.value)
+			continue
+		}
+		if message := result.FirstError().GetErrorMessage(); message != test.expected {
+			t.Errorf("%d: Expected message %q, got %q", i, test.expected, message)
+		}
+	}
+
+	result := schema.WithLocale("en").ValidateAll(M{"username": "a", "age": 1, "nickname": ""})
+	if message := result.Errors().First("nickname").GetErrorMessage(); message != "nickname explicit" {
+		t.Errorf("Expected explicit message, got %q", message)
+	}
+
+	if err := LoadMessages("testLocale", []byte(`{"between": 1}`)); err == nil {
+		t.Error("Expected invalid JSON messages to fail")
+	}
+
+	if err := LoadMessagesFile("testLocale", "not-exists.json"); err == nil {
+		t.Error("Expected missing messages file to fail")
+	}
+}
+
+func TestLocaleCatalogs(t *testing.T) {
+
+	t.Parallel()
+
+	for name, spec := range ruleSpecs {
+
+		r := &Rule{}
+		var args []string
+		switch spec.args {
+		case -1:
+			args = []string{"t1", "1"}
+		default:
+			for i := 0; i < spec.args; i++ {
+				args = append(args, "1")
+			}
+		}
+		if err := spec.build(r, args, ""); err != nil {
+			t.Fatal(name, err)
+		}
+
+		if it := r.item[0]; it.filter != nil || it.nullable {
+			continue
+		}
+
+		catalogs.RLock()
+		for _, locale := range []string{LocaleZhCN, LocaleEn} {
+			if _, ok := catalogs.messages[locale][r.item[0].name]; !ok {
+				t.Errorf("Expected %s message of rule %q", locale, r.item[0].name)
+			}
+		}
+		catalogs.RUnlock()
+	}
+}
+
+func TestLocaleCatalogsRef(t *testing.T) {
+
+	t.Parallel()
+
+	v := New()
+	v.AddColumn("confirm", "Confirm").EqualWithColumn("password", "")
+	v.AddColumn("username", "Username").DifferentWithColumn("password", "")
+	v.AddColumn("quantity", "Quantity").Max(Ref("stock"), "")
+
+	var tests = []*struct {
+		locale   string
+		value    M
+		expected string
+	}{
+		{LocaleZhCN, M{"password": "hunter2", "confirm": "hunter3"}, "Confirm必须等于password"},
+		{LocaleEn, M{"password": "hunter2", "confirm": "hunter3"}, "Confirm must equal password"},
+		{LocaleZhCN, M{"password": "hunter2", "username": "hunter2"}, "Username不能等于password"},
+		{LocaleEn, M{"password": "hunter2", "username": "hunter2"}, "Username must be different from password"},
+		{LocaleEn, M{"stock": 5, "quantity": 6}, "Quantity may not be greater than stock"},
+	}
+
+	schema := v.Schema()
+
+	for _, test := range tests {
+		result := schema.WithLocale(test.locale).Validate(test.value)
+		if result.Valid() || result.FirstError().Error() != test.expected {
+			t.Errorf("Expected %q, got %v", test.expected, result.FirstError())
+		}
+	}
+}